@@ -3,6 +3,7 @@ package api
 // TODO cache recipes in memory
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,8 +15,12 @@ import (
 	"git.sr.ht/~rottenfishbone/go-cook"
 	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
 	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/nutrition"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
 )
 
+var ErrRecipeNotFound = errors.New("File not found.")
+
 // Ensures the config file is loaded. Panic on failure.
 //
 // An unloaded read should never occur at runtime, unless there is a bug.
@@ -130,6 +135,21 @@ func collectRecipeFolders() []string {
 	return dirs
 }
 
+// Returns every (nested) subdirectory of the recipes directory as a filepath
+// relative to the recipe root. The root itself is included as "".
+func GetAllRecipeFolders() []string {
+	root := config.GetConfig().Recipe.Dir
+	folders := make([]string, 0)
+	for _, dir := range collectRecipeFolders() {
+		if dir == root {
+			folders = append(folders, "")
+		} else {
+			folders = append(folders, dir[len(root)+1:])
+		}
+	}
+	return folders
+}
+
 // Gathers all .cook recipes within the recipe root (possibly nested) and returns
 // each as a relative filepath from recipe root.
 //
@@ -203,7 +223,7 @@ func GetRecipeSource(name string) ([]byte, error) {
 
 	// Existence check
 	if !common.FileExists(path) {
-		return nil, errors.New("File not found.")
+		return nil, ErrRecipeNotFound
 	}
 
 	var raw []byte
@@ -221,7 +241,11 @@ func GetRecipeSource(name string) ([]byte, error) {
 //		Considerations:
 //		- byte array will be nil on failure
 //	 	- This can only read `.cook` files within the recipe directory.
-func GetRecipeJSON(name string) ([]byte, error) {
+//		- The recipe's `nutrition` (see `RecipeNutrition`) is included when
+//		  it can be calculated, e.g. if there is a nutrient table.
+//		- Temperatures are only parsed (see `cook.ParseTemperatures`) if
+//		  `temperatures` is set.
+func GetRecipeJSON(name string, temperatures bool) ([]byte, error) {
 	var err error
 	var raw []byte
 
@@ -230,13 +254,64 @@ func GetRecipeJSON(name string) ([]byte, error) {
 	}
 
 	r := cook.ParseRecipe(name, &raw)
+	if temperatures {
+		cook.ParseTemperatures(&r)
+	}
+	withNutrition := struct {
+		*cook.Recipe
+		Nutrition *nutrition.Facts `json:"nutrition,omitempty"`
+	}{Recipe: &r}
+	// Nutrition is only included when a nutrient table is set up
+	if facts, err := RecipeNutrition(name, &r); err == nil {
+		withNutrition.Nutrition = &facts
+	} else if !errors.Is(err, ErrNoNutritionTable) {
+		common.ShowError(fmt.Errorf("Failed to calculate the nutrition of %v: %w", name, err))
+	}
+
 	var jsonData []byte
-	if jsonData, err = json.Marshal(&r); err != nil {
+	if jsonData, err = json.Marshal(&withNutrition); err != nil {
 		return nil, err
 	}
 	return jsonData, nil
 }
 
+// Renders the specified recipe in any format registered with `recipe.GetRenderer`
+// (e.g. "jsonld"), using the unit system from the config.
+// Recipe is specified as a relative filepath from directory root
+//
+// e.g. "breakfast/eggs_benedict"
+//
+//	Considerations:
+//	- byte array will be nil on failure
+//	- Unknown formats return an error listing the available formats
+//	- Temperatures are only parsed (see `cook.ParseTemperatures`) if
+//	  `temperatures` is set.
+func GetRecipeFormatted(name string, format string, temperatures bool) ([]byte, error) {
+	var err error
+	var renderer recipe.Renderer
+
+	if renderer, err = recipe.GetRenderer(format); err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	if raw, err = GetRecipeSource(name); err != nil {
+		return nil, err
+	}
+
+	opts := recipe.RenderOptions{Units: config.GetConfig().Units, Temperatures: temperatures}
+	r := cook.ParseRecipe(recipe.FilepathToName(name), &raw)
+	if opts.Temperatures {
+		cook.ParseTemperatures(&r)
+	}
+
+	var buf bytes.Buffer
+	if err = renderer.Render(&buf, &r, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // Replaces contents of specified recipe with `contents`
 // Recipe is specified as a relative filepath from directory root
 //
@@ -375,6 +450,11 @@ func CreateRecipe(name string, contents *[]byte) error {
 		return errors.New("File already exists.")
 	}
 
+	// Ensure the recipe's folder exists
+	if err = os.MkdirAll(filepath.Dir(name), os.ModePerm); err != nil {
+		return err
+	}
+
 	// Create the new recipe file
 	if file, err = os.Create(name); err != nil {
 		return err
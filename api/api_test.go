@@ -0,0 +1,98 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"github.com/BurntSushi/toml"
+)
+
+// Loads a config with a temporary recipes directory holding `recipes` (keyed
+// by name, e.g. "sauces/ragu"), returning the directory.
+func loadTestRecipes(t *testing.T, recipes map[string]string) string {
+	return loadTestConfig(t, config.Config{}, recipes)
+}
+
+// As `loadTestRecipes`, with the rest of the config taken from `conf`.
+func loadTestConfig(t *testing.T, conf config.Config, recipes map[string]string) string {
+	dir := t.TempDir()
+	conf.Recipe.Dir = filepath.Join(dir, "recipes")
+	conf.Shopping.Dir = filepath.Join(dir, "shopping")
+
+	for name, source := range recipes {
+		path := filepath.Join(conf.Recipe.Dir, filepath.FromSlash(name)+".cook")
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(conf.Recipe.Dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(dir, "config.toml")
+	file, err := os.Create(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err = toml.NewEncoder(file).Encode(conf); err != nil {
+		t.Fatal(err)
+	}
+	if !config.LoadConfig(configPath) {
+		t.Fatal("Failed to load test config")
+	}
+	return conf.Recipe.Dir
+}
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestGetRecipeTemperatures(t *testing.T) {
+	loadTestRecipes(t, map[string]string{"bread": "Bake at 200°C.\n"})
+
+	// Temperatures are left as text unless asked for
+	testRecipe := func(temperatures bool, json string, text string) {
+		data, err := GetRecipeJSON("bread", temperatures)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), json) {
+			t.Fatalf("Wrong recipe JSON\ngot: %s\nwant: %v.", data, json)
+		}
+
+		if data, err = GetRecipeFormatted("bread", "text", temperatures); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Wrong recipe text\ngot: %s\nwant: %v.", data, text)
+		}
+	}
+	testRecipe(false, `[{"tag":"text","data":"Bake at 200°C."}]`, "Bake at 200°C.\n")
+	testRecipe(true, `{"tag":"temperature","data":{"value":200,"scale":"C","raw":"200°C"}}`,
+		"Bake at 200°C (392°F).")
+}
+
+func TestCreateRecipe(t *testing.T) {
+	recipes := loadTestRecipes(t, map[string]string{})
+
+	// Missing folders are created
+	contents := []byte("Blend @basil{50%g} with @pine nuts{30%g}.\n")
+	if err := CreateRecipe("sauces/green/pesto", &contents); err != nil {
+		t.Fatalf("Failed to create a nested recipe: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(recipes, "sauces", "green", "pesto.cook"))
+	if err != nil || string(data) != string(contents) {
+		t.Fatalf("Wrong recipe file: %v\ngot: %s\nwant: %s.", err, data, contents)
+	}
+
+	if err = CreateRecipe("sauces/green/pesto", &contents); err == nil {
+		t.Fatal("Overwrote an existing recipe")
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/plan"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+var ErrNoHMACKey = errors.New("No hmac-key is configured, see `cook init`.")
+
+// The message signed by calendar feed tokens
+const planFeedMessage = "go-cook plan.ics"
+
+// Returns the token which grants access to the meal plan's calendar feed,
+// signed with the server's key. Changing the key revokes it.
+func PlanFeedToken() (string, error) {
+	key := config.GetHMACKeyBytes()
+	if len(key) == 0 {
+		return "", ErrNoHMACKey
+	}
+
+	mac := hmac.New(sha512.New, key)
+	mac.Write([]byte(planFeedMessage))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Returns whether `token` grants access to the meal plan's calendar feed
+func ValidatePlanFeedToken(token string) bool {
+	want, err := PlanFeedToken()
+	if err != nil {
+		return false
+	}
+	// Compared with hmac.Equal to avoid timing attacks
+	return hmac.Equal([]byte(token), []byte(want))
+}
+
+// Returns the address of the webapp's page for a recipe, e.g.
+// "http://localhost:6969/?recipe=mains%2Flasagna"
+func RecipePageURL(baseURL string, name string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/?recipe=" + url.QueryEscape(name)
+}
+
+// Builds the calendar event of a planned meal, listing the ingredients of its
+// recipe scaled to the servings planned.
+func planEvent(meal plan.Meal, baseURL string) plan.Event {
+	event := plan.Event{Meal: meal, Title: recipe.FilepathToName(meal.Recipe)}
+	if baseURL != "" {
+		event.URL = RecipePageURL(baseURL, meal.Recipe)
+	}
+
+	r, err := loadRecipe(meal.Recipe)
+	if err != nil {
+		// Removed recipes are still shown, without ingredients
+		return event
+	}
+	if title, ok := r.Metadata.Get("title"); ok && strings.TrimSpace(title) != "" {
+		event.Title = strings.TrimSpace(title)
+	}
+	cook.ScaleRecipe(r, servingsFactor(r, meal.Servings))
+
+	for _, ingr := range r.Ingredients {
+		name := ingr.Name
+		if ingr.IsRecipeRef() {
+			name = recipe.FilepathToName(ingr.Name)
+		}
+		amount := recipe.FormatAmount(cook.Component(ingr))
+		event.Ingredients = append(event.Ingredients, strings.TrimSpace(amount+" "+name))
+	}
+	return event
+}
+
+// Writes every planned meal as an iCalendar feed (see `plan.WriteICS`), with
+// meal times and durations from the `[calendar]` config. Events link to the
+// recipe pages of the webapp at `baseURL`, unless it is empty.
+func WritePlanICS(w io.Writer, baseURL string) error {
+	var err error
+	conf := config.GetConfig().Calendar
+
+	opts := plan.ICSOptions{MealTimes: conf.MealTimes, Stamp: time.Now()}
+	if conf.Duration != "" {
+		if opts.Duration, err = units.ParseDuration(conf.Duration); err != nil {
+			return errors.New("Invalid calendar duration in config: " + conf.Duration)
+		}
+	}
+	for meal, start := range conf.MealTimes {
+		if _, err = time.Parse("15:04", strings.TrimSpace(start)); err != nil {
+			return errors.New("Invalid calendar time of " + meal + " in config: " + start)
+		}
+	}
+
+	var p plan.Plan
+	if p, err = GetPlan(); err != nil {
+		return err
+	}
+
+	events := make([]plan.Event, 0, len(p.Meals))
+	for _, meal := range p.Meals {
+		events = append(events, planEvent(meal, baseURL))
+	}
+	return plan.WriteICS(w, events, opts)
+}
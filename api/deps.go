@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+var (
+	ErrRecipeCycle     = errors.New("Recipe reference cycle")
+	ErrBrokenRecipeRef = errors.New("Broken recipe reference")
+)
+
+// The recipes a single recipe references (Uses) and is referenced by (UsedBy).
+//
+// Recipes are represented as a path relative to the recipes root.
+type RecipeDeps struct {
+	Name   string   `json:"name"`
+	Uses   []string `json:"uses"`
+	UsedBy []string `json:"usedBy"`
+}
+
+// The dependency graph of the whole recipe library, keyed by recipe name.
+type DependencyGraph map[string]*RecipeDeps
+
+// Reads and parses the recipe at the provided relative filepath.
+//
+// e.g. "breakfast/eggs_benedict"
+func loadRecipe(name string) (*cook.Recipe, error) {
+	var err error
+	var raw []byte
+
+	if raw, err = GetRecipeSource(name); err != nil {
+		return nil, err
+	}
+
+	r := cook.ParseRecipe(name, &raw)
+	return &r, nil
+}
+
+// Resolves a recipe reference (e.g. `@./sauces/hollandaise{}`) made from within
+// recipe `from` to the referenced recipe's name.
+//
+// References are relative to the directory of the referencing recipe.
+//
+// e.g. ("mains/eggs_benedict", "../sauces/hollandaise") -> "sauces/hollandaise"
+func ResolveRecipeRef(from string, ref string) string {
+	path := filepath.Join(filepath.Dir(from), filepath.FromSlash(ref))
+	return strings.TrimSuffix(path, ".cook")
+}
+
+// Returns the (de-duplicated) names of every recipe referenced by `r`, which
+// is named `name`.
+func recipeRefs(name string, r *cook.Recipe) []string {
+	refs := make([]string, 0)
+	seen := map[string]bool{}
+	for _, ingr := range r.Ingredients {
+		if !ingr.IsRecipeRef() {
+			continue
+		}
+
+		ref := ResolveRecipeRef(name, ingr.Name)
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Builds the dependency graph of every recipe in the recipe directory.
+//
+// References to recipes that don't exist are kept in `Uses`, but have no
+// node of their own in the graph.
+//
+// Returns (nil, err) on failure
+func BuildDependencyGraph() (DependencyGraph, error) {
+	var err error
+	var names []string
+
+	if names, err = GetAllRecipeNames(); err != nil {
+		return nil, err
+	}
+
+	graph := DependencyGraph{}
+	for _, name := range names {
+		graph[name] = &RecipeDeps{Name: name, Uses: []string{}, UsedBy: []string{}}
+	}
+
+	for _, name := range names {
+		var r *cook.Recipe
+		if r, err = loadRecipe(name); err != nil {
+			return nil, err
+		}
+
+		graph[name].Uses = recipeRefs(name, r)
+		for _, ref := range graph[name].Uses {
+			if dep, ok := graph[ref]; ok {
+				dep.UsedBy = append(dep.UsedBy, name)
+			}
+		}
+	}
+
+	// Stable output, regardless of directory read order
+	for _, deps := range graph {
+		sort.Strings(deps.Uses)
+		sort.Strings(deps.UsedBy)
+	}
+
+	return graph, nil
+}
+
+// Returns the recipes used by, and using, the recipe at the provided relative
+// filepath.
+//
+// e.g. "breakfast/eggs_benedict"
+func GetRecipeDeps(name string) (RecipeDeps, error) {
+	var err error
+	var graph DependencyGraph
+
+	if graph, err = BuildDependencyGraph(); err != nil {
+		return RecipeDeps{}, err
+	}
+
+	name = filepath.Clean(filepath.FromSlash(strings.TrimSuffix(name, ".cook")))
+	deps, ok := graph[name]
+	if !ok {
+		return RecipeDeps{}, ErrRecipeNotFound
+	}
+	return *deps, nil
+}
+
+// Flattens a recipe and all of its (nested) sub-recipes into one combined
+// ingredient list. Each recipe reference is replaced by the ingredients of the
+// referenced recipe, scaled by the amount referenced (see `cook.RecipeRefScale`).
+//
+// `factor` scales the top-level recipe, use 1 to leave it unscaled.
+// References with a unit other than servings (e.g. `{150%g}`) can't be scaled,
+// so they include the whole sub-recipe.
+//
+// Returns `ErrRecipeNotFound` if the recipe is missing, `ErrBrokenRecipeRef` if
+// a sub-recipe is, and `ErrRecipeCycle` on reference cycles.
+func ExpandRecipe(name string, factor float64) ([]cook.Ingredient, error) {
+	var err error
+	var r *cook.Recipe
+
+	if r, err = loadRecipe(name); err != nil {
+		return nil, err
+	}
+
+	var ingredients []cook.Ingredient
+	if ingredients, err = expandRecipe(name, r, factor, []string{}); err != nil {
+		return nil, err
+	}
+	return cook.CombineIngredients(ingredients), nil
+}
+
+// Recursive helper to `ExpandRecipe`, `path` is the chain of recipes which led
+// to `name` (used to detect cycles).
+func expandRecipe(name string, r *cook.Recipe, factor float64, path []string) ([]cook.Ingredient, error) {
+	for _, visited := range path {
+		if visited == name {
+			cycle := strings.Join(append(path, name), " -> ")
+			return nil, fmt.Errorf("%w: %s", ErrRecipeCycle, cycle)
+		}
+	}
+	path = append(path, name)
+
+	// References are scaled against their original amounts
+	unscaled := append([]cook.Ingredient{}, r.Ingredients...)
+	cook.ScaleRecipe(r, factor)
+
+	ingredients := make([]cook.Ingredient, 0, len(r.Ingredients))
+	for i, ingr := range r.Ingredients {
+		if !ingr.IsRecipeRef() {
+			ingredients = append(ingredients, ingr)
+			continue
+		}
+
+		// Expand the sub-recipe in place of the reference
+		var err error
+		var sub *cook.Recipe
+		subName := ResolveRecipeRef(name, ingr.Name)
+		if sub, err = loadRecipe(subName); err != nil {
+			return nil, fmt.Errorf("%w: failed to load %s (used by %s): %w", ErrBrokenRecipeRef, subName, name, err)
+		}
+
+		var subIngredients []cook.Ingredient
+		subFactor := factor * cook.RecipeRefScale(unscaled[i], sub)
+		if subIngredients, err = expandRecipe(subName, sub, subFactor, path); err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, subIngredients...)
+	}
+
+	return ingredients, nil
+}
+
+// Returns the dependencies and the flattened ingredient list of the recipe at
+// the provided relative filepath as JSON.
+//
+// See `GetRecipeDeps` and `ExpandRecipe` for details.
+func GetRecipeDepsJSON(name string) ([]byte, error) {
+	var err error
+
+	var deps RecipeDeps
+	if deps, err = GetRecipeDeps(name); err != nil {
+		return nil, err
+	}
+
+	var ingredients []cook.Ingredient
+	if ingredients, err = ExpandRecipe(name, 1); err != nil {
+		return nil, err
+	}
+
+	out := struct {
+		RecipeDeps
+		Ingredients []cook.Ingredient `json:"ingredients"`
+	}{deps, ingredients}
+
+	var jsonBytes []byte
+	if jsonBytes, err = json.Marshal(out); err != nil {
+		return nil, err
+	}
+	return jsonBytes, nil
+}
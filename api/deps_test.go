@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestBuildDependencyGraph(t *testing.T) {
+	loadTestRecipes(t, map[string]string{
+		"mains/eggs_benedict": "Top @eggs{2} with @../sauces/hollandaise{150%g} and @./missing{}.\n",
+		"mains/brunch":        "Serve @./eggs_benedict{2} and @../sauces/hollandaise{}.\n",
+		"sauces/hollandaise":  ">> servings: 2\nWhisk @butter{100%g} and @egg yolks{3}.\n",
+	})
+
+	graph, err := BuildDependencyGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DependencyGraph{
+		"mains/eggs_benedict": {Name: "mains/eggs_benedict",
+			Uses: []string{"mains/missing", "sauces/hollandaise"}, UsedBy: []string{"mains/brunch"}},
+		"mains/brunch": {Name: "mains/brunch",
+			Uses: []string{"mains/eggs_benedict", "sauces/hollandaise"}, UsedBy: []string{}},
+		"sauces/hollandaise": {Name: "sauces/hollandaise",
+			Uses: []string{}, UsedBy: []string{"mains/brunch", "mains/eggs_benedict"}},
+	}
+	if !reflect.DeepEqual(graph, want) {
+		for name, deps := range graph {
+			t.Logf("%v: %+v", name, *deps)
+		}
+		t.Fatal("Wrong dependency graph")
+	}
+
+	if _, err = GetRecipeDeps("mains/nothing"); !errors.Is(err, ErrRecipeNotFound) {
+		t.Fatalf("Wrong error for a missing recipe: %v", err)
+	}
+}
+
+func TestExpandRecipe(t *testing.T) {
+	loadTestRecipes(t, map[string]string{
+		"mains/eggs_benedict": ">> servings: 2\n" +
+			"Toast @muffins{2} and top with @eggs{2}, @../sauces/hollandaise{1%serving} and @salt{}.\n",
+		"mains/brunch":       "Serve @./eggs_benedict{2} with @eggs{2}.\n",
+		"mains/toast":        "Spread @../sauces/hollandaise{150%g} on @bread{2%slices}.\n",
+		"sauces/hollandaise": ">> servings: 2\nWhisk @butter{100%g} with @eggs{2}.\n",
+		"loops/a":            "Mix @./b{}.\n",
+		"loops/b":            "Mix @./a{} with @salt{}.\n",
+		"broken":             "Mix @./nowhere{} with @salt{}.\n",
+	})
+
+	expand := func(name string, factor float64) map[string]string {
+		ingredients, err := ExpandRecipe(name, factor)
+		if err != nil {
+			t.Fatalf("Failed to expand %v: %v", name, err)
+		}
+		got := map[string]string{}
+		for _, ingr := range ingredients {
+			got[ingr.Name] = recipe.FormatAmount(cook.Component(ingr))
+		}
+		return got
+	}
+
+	// Half a hollandaise recipe is used, eggs are combined
+	want := map[string]string{"muffins": "2", "eggs": "3", "butter": "50 g", "salt": ""}
+	if got := expand("mains/eggs_benedict", 1); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Wrong expansion\ngot: %v\nwant: %v", got, want)
+	}
+	// Scaling carries through nested references
+	want = map[string]string{"muffins": "8", "eggs": "16", "butter": "200 g", "salt": ""}
+	if got := expand("mains/brunch", 2); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Wrong scaled expansion\ngot: %v\nwant: %v", got, want)
+	}
+	// References by weight can't be scaled, so the whole recipe is used
+	want = map[string]string{"butter": "100 g", "eggs": "2", "bread": "2 slices"}
+	if got := expand("mains/toast", 1); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Wrong expansion of a reference by weight\ngot: %v\nwant: %v", got, want)
+	}
+
+	if _, err := ExpandRecipe("loops/a", 1); !errors.Is(err, ErrRecipeCycle) {
+		t.Fatalf("Cycle not detected, got: %v", err)
+	}
+	if _, err := ExpandRecipe("broken", 1); !errors.Is(err, ErrBrokenRecipeRef) {
+		t.Fatalf("Broken reference not detected, got: %v", err)
+	}
+	if _, err := ExpandRecipe("nothing", 1); !errors.Is(err, ErrRecipeNotFound) {
+		t.Fatalf("Wrong error for a missing recipe: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/importer"
+)
+
+// The outcome of importing an export from another app.
+type ImportReport struct {
+	Imported []string           `json:"imported"` // Names of the saved recipes
+	Failed   []importer.Failure `json:"failed"`
+}
+
+// Saves recipes read from another app's export into the recipes directory.
+//
+// With `categoryFolders`, each recipe is saved within a folder named after its
+// first category and any others become tags; otherwise every category becomes
+// a tag. Photos are saved next to their recipe, sharing its name (e.g.
+// `toast.cook` and `toast.jpg`).
+//
+// Existing recipes are never overwritten, these are reported as failures
+// along with any recipe which couldn't be saved.
+func ImportRecipes(recipes []importer.ArchivedRecipe, categoryFolders bool) ImportReport {
+	report := ImportReport{Imported: []string{}, Failed: []importer.Failure{}}
+	fail := func(name string, err error) {
+		report.Failed = append(report.Failed, importer.Failure{Name: name, Reason: err.Error()})
+	}
+
+	for _, r := range recipes {
+		name := importer.FileName(r.Name)
+		categories := r.Categories
+		if categoryFolders && len(categories) > 0 {
+			name = path.Join(importer.FileName(categories[0]), name)
+			categories = categories[1:]
+		}
+		for _, category := range categories {
+			r.Tags = append(r.Tags, strings.ToLower(category))
+		}
+
+		recipePath, err := sanitizeRecipeName(name)
+		if err != nil {
+			fail(r.Name, err)
+			continue
+		}
+		if err = os.MkdirAll(filepath.Dir(recipePath), os.ModePerm); err != nil {
+			fail(r.Name, err)
+			continue
+		}
+
+		source := []byte(importer.ToCooklang(r.Recipe))
+		if err = CreateRecipe(name, &source); err != nil {
+			fail(r.Name, err)
+			continue
+		}
+		report.Imported = append(report.Imported, name)
+
+		if r.Photo != nil {
+			photoPath := strings.TrimSuffix(recipePath, ".cook") + r.PhotoExt
+			if common.FileExists(photoPath) {
+				continue
+			}
+			if err = os.WriteFile(photoPath, r.Photo, 0644); err != nil {
+				fail(r.Name+" (photo)", err)
+			}
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+// Names of each rule checked by the linter. Their severities can be set
+// in the `[lint.rules]` table of the config.
+const (
+	LintInconsistentUnits = "inconsistent-units"
+	LintUnknownUnit       = "unknown-unit"
+	LintUnparsableQty     = "unparsable-qty"
+	LintTimerNoUnit       = "timer-no-unit"
+	LintDuplicateMetadata = "duplicate-metadata"
+	LintEmptyStep         = "empty-step"
+	LintMissingRecipeRef  = "missing-recipe-ref"
+)
+
+// Rule severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+	SeverityOff     = "off"
+)
+
+// A single problem found by the linter, positioned within the recipe source.
+type LintIssue struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"` // 1-based
+	Col      int    `json:"col"`  // 1-based, in characters
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+// Formats an issue compiler-style.
+//
+// e.g. `mains/pasta.cook:3:12: warning: unknown unit "cupz" [unknown-unit]`
+func (issue LintIssue) String() string {
+	return fmt.Sprintf("%v:%v:%v: %v: %v [%v]",
+		issue.File, issue.Line, issue.Col, issue.Severity, issue.Message, issue.Rule)
+}
+
+// Returns the configured severity of `rule`, defaulting to a warning.
+func lintSeverity(rule string) string {
+	severity := strings.ToLower(config.GetConfig().Lint.Rules[rule])
+	switch severity {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return severity
+	default:
+		return SeverityWarning
+	}
+}
+
+// Matches line (`-- ...`) and block (`[- ... -]`) comments
+var commentRegex = regexp.MustCompile(`(--.*)|(\[-(.|\s)*?-\])`)
+
+// Replaces comments in a recipe source with whitespace, leaving line and
+// column positions of everything else intact.
+//
+// Returns the blanked source and whether each line contained a comment.
+func blankComments(src string) (string, []bool) {
+	blanked := commentRegex.ReplaceAllStringFunc(src, func(comment string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '\n' || r == '\r' {
+				return r
+			}
+			return ' '
+		}, comment)
+	})
+
+	// Flag each line that had a comment removed
+	srcLines := strings.Split(src, "\n")
+	blankedLines := strings.Split(blanked, "\n")
+	commented := make([]bool, len(srcLines))
+	for i := range srcLines {
+		commented[i] = srcLines[i] != blankedLines[i]
+	}
+
+	return blanked, commented
+}
+
+// A single use of an ingredient within the source, used to compare units
+type ingredientUse struct {
+	unit      string
+	line, col int
+}
+
+// Checks a recipe's source against each lint rule.
+//
+// `name` is the recipe's relative filepath (used to resolve recipe references)
+// and `file` is the path reported with each issue.
+func lintSource(name string, file string, src []byte) []LintIssue {
+	issues := make([]LintIssue, 0)
+	report := func(rule string, line int, col int, msg string, args ...any) {
+		severity := lintSeverity(rule)
+		if severity == SeverityOff {
+			return
+		}
+		issues = append(issues, LintIssue{
+			File:     file,
+			Line:     line,
+			Col:      col,
+			Severity: severity,
+			Rule:     rule,
+			Message:  fmt.Sprintf(msg, args...),
+		})
+	}
+
+	blanked, commented := blankComments(string(src))
+	metaLines := map[string]int{}
+	uses := map[string][]ingredientUse{}
+	useOrder := make([]string, 0)
+
+	// Each line is parsed alone, so that positions can be reported
+	for i, line := range strings.Split(blanked, "\n") {
+		lineNum := i + 1
+		line = strings.TrimSuffix(line, "\r")
+
+		// Blank lines only separate steps
+		if line == "" || (commented[i] && strings.TrimSpace(line) == "") {
+			continue
+		}
+
+		// Whitespace is still parsed as a step
+		if strings.TrimSpace(line) == "" {
+			report(LintEmptyStep, lineNum, 1, "step is empty")
+			continue
+		}
+
+		r := cook.ParseRecipeString("", line)
+
+		// Metadata
+		for _, key := range r.Metadata.Keys() {
+			col := strings.Index(line, ">>") + 1
+			if first, ok := metaLines[key]; ok {
+				report(LintDuplicateMetadata, lineNum, col,
+					"metadata %q is already defined on line %v", key, first)
+				continue
+			}
+			metaLines[key] = lineNum
+		}
+
+		// Components of the step, these are found in order by their specifier
+		cursor := 0
+		locate := func(prefix string) int {
+			idx := strings.Index(line[cursor:], prefix)
+			if idx < 0 {
+				return utf8.RuneCountInString(line[:cursor]) + 1
+			}
+			cursor += idx
+			col := utf8.RuneCountInString(line[:cursor]) + 1
+			cursor += len(prefix)
+			return col
+		}
+
+		for _, step := range r.Steps {
+			for _, chunk := range step {
+				switch chunk := chunk.(type) {
+				case cook.Ingredient:
+					col := locate("@" + chunk.Name)
+					if chunk.Qty != "" && chunk.QtyVal == cook.NoQty {
+						report(LintUnparsableQty, lineNum, col,
+							"quantity %q of %q is not a number", chunk.Qty, chunk.Name)
+					}
+
+					if chunk.IsRecipeRef() {
+						ref := ResolveRecipeRef(name, chunk.Name)
+						path, err := sanitizeRecipeName(ref)
+						if err != nil || !common.FileExists(path) {
+							report(LintMissingRecipeRef, lineNum, col,
+								"referenced recipe %q does not exist", ref)
+						}
+						continue
+					}
+
+					if chunk.Unit != "" && !units.Known(chunk.Unit) {
+						report(LintUnknownUnit, lineNum, col,
+							"unknown unit %q for %q", chunk.Unit, chunk.Name)
+					}
+
+					key := strings.ToLower(chunk.Name)
+					if _, ok := uses[key]; !ok {
+						useOrder = append(useOrder, key)
+					}
+					uses[key] = append(uses[key], ingredientUse{chunk.Unit, lineNum, col})
+				case cook.Cookware:
+					col := locate("#" + chunk.Name)
+					if chunk.Qty != "" && chunk.QtyVal == cook.NoQty {
+						report(LintUnparsableQty, lineNum, col,
+							"quantity %q of %q is not a number", chunk.Qty, chunk.Name)
+					}
+				case cook.Timer:
+					col := locate("~" + chunk.Name)
+					if chunk.Qty != "" && chunk.QtyVal == cook.NoQty {
+						report(LintUnparsableQty, lineNum, col,
+							"timer quantity %q is not a number", chunk.Qty)
+					}
+
+					if chunk.Unit == "" {
+						report(LintTimerNoUnit, lineNum, col, "timer has no unit")
+					} else if u, ok := units.Lookup(chunk.Unit); !ok || u.Dimension != units.Time {
+						report(LintUnknownUnit, lineNum, col,
+							"timer unit %q is not a unit of time", chunk.Unit)
+					}
+				}
+			}
+		}
+	}
+
+	// Compare every use of an ingredient against its first use
+	for _, key := range useOrder {
+		first := uses[key][0]
+		for _, use := range uses[key][1:] {
+			if units.Compatible(first.unit, use.unit) {
+				continue
+			}
+			report(LintInconsistentUnits, use.line, use.col,
+				"%q is measured in %q here, but in %q on line %v",
+				key, use.unit, first.unit, first.line)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Col < issues[j].Col
+	})
+	return issues
+}
+
+// Checks the recipe at the provided relative filepath for common mistakes.
+//
+// e.g. "breakfast/eggs_benedict"
+//
+// Issues are reported with the recipe's path (relative to the recipes root).
+func LintRecipe(name string) ([]LintIssue, error) {
+	var err error
+	var raw []byte
+
+	if raw, err = GetRecipeSource(name); err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSuffix(name, ".cook")
+	return lintSource(name, name+".cook", raw), nil
+}
+
+// Checks every recipe in the recipes directory, see `LintRecipe`.
+func LintAllRecipes() ([]LintIssue, error) {
+	var err error
+	var names []string
+
+	if names, err = GetAllRecipeNames(); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	issues := make([]LintIssue, 0)
+	for _, name := range names {
+		var recipeIssues []LintIssue
+		if recipeIssues, err = LintRecipe(name); err != nil {
+			return nil, err
+		}
+		issues = append(issues, recipeIssues...)
+	}
+
+	return issues, nil
+}
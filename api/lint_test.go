@@ -0,0 +1,110 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+)
+
+// Lints `src` as the recipe "mains/test", alongside an existing "mains/gravy",
+// returning each issue formatted.
+func lintTestSource(t *testing.T, conf config.Config, src string) []string {
+	loadTestConfig(t, conf, map[string]string{
+		"mains/test":  src,
+		"mains/gravy": "Whisk @flour{2%tbsp} into @stock{500%ml}.\n",
+	})
+
+	issues, err := LintRecipe("mains/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	formatted := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		formatted = append(formatted, issue.String())
+	}
+	return formatted
+}
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestLintRules(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{"clean",
+			">> servings: 2\nMix @flour{1%cup} in a #bowl{} for ~{2%minutes}.\n\nServe with @./gravy{}.\n",
+			[]string{}},
+		{LintInconsistentUnits,
+			"Mix @flour{1%cup}.\nAdd @Flour{100%g} and @flour{2%tbsp}.\n",
+			[]string{`mains/test.cook:2:5: warning: "flour" is measured in "g" here, but in "cup" on line 1 [inconsistent-units]`}},
+		{LintUnknownUnit,
+			"Add @sugar{2%cupz}.\n",
+			[]string{`mains/test.cook:1:5: warning: unknown unit "cupz" for "sugar" [unknown-unit]`}},
+		{LintUnknownUnit + " (timer)",
+			"Wait ~rest{10%cups}.\n",
+			[]string{`mains/test.cook:1:6: warning: timer unit "cups" is not a unit of time [unknown-unit]`}},
+		{LintUnparsableQty,
+			"Add @salt{a pinch} and #pans{a few}.\n",
+			[]string{
+				`mains/test.cook:1:5: warning: quantity "a pinch" of "salt" is not a number [unparsable-qty]`,
+				`mains/test.cook:1:24: warning: quantity "a few" of "pans" is not a number [unparsable-qty]`,
+			}},
+		{LintTimerNoUnit,
+			"Wait ~{10}.\n",
+			[]string{`mains/test.cook:1:6: warning: timer has no unit [timer-no-unit]`}},
+		{LintDuplicateMetadata,
+			">> servings: 2\n>> servings: 4\n",
+			[]string{`mains/test.cook:2:1: warning: metadata "servings" is already defined on line 1 [duplicate-metadata]`}},
+		{LintEmptyStep,
+			"Mix.\n   \nServe.\n",
+			[]string{`mains/test.cook:2:1: warning: step is empty [empty-step]`}},
+		{LintMissingRecipeRef,
+			"Serve with @./sauces/missing{} and @./gravy{}.\n",
+			[]string{`mains/test.cook:1:12: warning: referenced recipe "mains/sauces/missing" does not exist [missing-recipe-ref]`}},
+		{"comments",
+			"Add @sugar{2%cupz} -- and @salt{a pinch}\n[- @flour{1%cupz}\n   -]\nServe.\n",
+			[]string{`mains/test.cook:1:5: warning: unknown unit "cupz" for "sugar" [unknown-unit]`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := lintTestSource(t, config.Config{}, test.src)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("Wrong issues\ngot: %q\nwant: %q.", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLintSeverity(t *testing.T) {
+	conf := config.Config{Lint: config.LintConfig{Rules: map[string]string{
+		LintUnknownUnit:       "error",
+		LintTimerNoUnit:       "INFO",
+		LintEmptyStep:         "off",
+		LintInconsistentUnits: "fatal",
+	}}}
+	src := "Add @sugar{2%cupz}.\n \nWait ~{10}.\nAdd @sugar{1%g}.\n"
+
+	got := lintTestSource(t, conf, src)
+	want := []string{
+		`mains/test.cook:1:5: error: unknown unit "cupz" for "sugar" [unknown-unit]`,
+		`mains/test.cook:3:6: info: timer has no unit [timer-no-unit]`,
+		`mains/test.cook:4:5: warning: "sugar" is measured in "g" here, but in "cupz" on line 1 [inconsistent-units]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Wrong issues\ngot: %q\nwant: %q.", got, want)
+	}
+}
+
+func TestLintRecipeMissing(t *testing.T) {
+	loadTestRecipes(t, map[string]string{})
+	if _, err := LintRecipe("mains/nothing"); !errors.Is(err, ErrRecipeNotFound) {
+		t.Fatalf("Wrong error for a missing recipe: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/nutrition"
+)
+
+var ErrNoNutritionTable = errors.New("No nutrient table")
+
+// The last loaded nutrient database, reused until any of its files change
+var nutritionCache struct {
+	sync.Mutex
+	loaded   bool
+	paths    config.NutritionConfig
+	modTimes [3]time.Time
+	db       nutrition.DB
+}
+
+// Returns the modification time of a file, zero if it doesn't exist
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Loads the nutrient table, its overrides and aliases (see
+// `config.NutritionPaths`). The database is kept in memory and only read
+// again once one of the files is modified.
+//
+// Returns `ErrNoNutritionTable` if the table doesn't exist.
+func LoadNutritionDB() (nutrition.DB, error) {
+	paths := config.NutritionPaths()
+	if !common.FileExists(paths.Table) {
+		return nutrition.DB{}, fmt.Errorf("%w at %v", ErrNoNutritionTable, paths.Table)
+	}
+	modTimes := [3]time.Time{modTime(paths.Table), modTime(paths.Overrides), modTime(paths.Aliases)}
+
+	nutritionCache.Lock()
+	defer nutritionCache.Unlock()
+	if nutritionCache.loaded && nutritionCache.paths == paths && nutritionCache.modTimes == modTimes {
+		return nutritionCache.db, nil
+	}
+
+	db, err := nutrition.Load(paths.Table, paths.Overrides, paths.Aliases)
+	if err != nil {
+		return nutrition.DB{}, err
+	}
+	nutritionCache.loaded = true
+	nutritionCache.paths = paths
+	nutritionCache.modTimes = modTimes
+	nutritionCache.db = db
+	return db, nil
+}
+
+// Calculates the nutrition of a parsed recipe, in total and per serving (see
+// `nutrition.DB.Calculate`). Referenced recipes are expanded into their
+// ingredients, resolved from `name` as in `ExpandRecipe`.
+func RecipeNutrition(name string, r *cook.Recipe) (nutrition.Facts, error) {
+	var err error
+
+	var db nutrition.DB
+	if db, err = LoadNutritionDB(); err != nil {
+		return nutrition.Facts{}, err
+	}
+
+	servings, _ := cook.RecipeServings(r)
+	var ingredients []cook.Ingredient
+	if ingredients, err = expandRecipe(name, r, 1, []string{}); err != nil {
+		return nutrition.Facts{}, err
+	}
+	return db.Calculate(cook.CombineIngredients(ingredients), servings), nil
+}
+
+// Calculates the nutrition of the recipe at the provided relative filepath,
+// see `RecipeNutrition`.
+func GetRecipeNutrition(name string) (nutrition.Facts, error) {
+	r, err := loadRecipe(name)
+	if err != nil {
+		return nutrition.Facts{}, err
+	}
+	return RecipeNutrition(name, r)
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestLoadNutritionDB(t *testing.T) {
+	recipes := loadTestRecipes(t, map[string]string{"toast": "Toast @bread{100%g}.\n"})
+	table := filepath.Join(filepath.Dir(recipes), "nutrition.csv")
+
+	if _, err := LoadNutritionDB(); !errors.Is(err, ErrNoNutritionTable) {
+		t.Fatalf("Wrong error without a nutrient table: %v", err)
+	}
+	if data, err := GetRecipeJSON("toast", false); err != nil || strings.Contains(string(data), "nutrition") {
+		t.Fatalf("Wrong recipe without a nutrient table: %v\n%s", err, data)
+	}
+
+	// Writes the table with a fixed modification time
+	writeTable := func(contents string, modified time.Time) {
+		if err := os.WriteFile(table, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(table, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testFoods := func(want int) {
+		db, err := LoadNutritionDB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if db.Len() != want {
+			t.Fatalf("Wrong number of foods\ngot: %v\nwant: %v.", db.Len(), want)
+		}
+	}
+	modified := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeTable("name,calories\nBread,250\n", modified)
+	testFoods(1)
+	if data, err := GetRecipeJSON("toast", false); err != nil || !strings.Contains(string(data), `"nutrition"`) {
+		t.Fatalf("Recipe is missing its nutrition: %v\n%s", err, data)
+	}
+
+	// The table is only read again once it is modified
+	writeTable("name,calories\nBread,250\nButter,717\n", modified)
+	testFoods(1)
+	writeTable("name,calories\nBread,250\nButter,717\n", modified.Add(time.Minute))
+	testFoods(2)
+}
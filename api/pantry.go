@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"sync"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/importer"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/pantry"
+)
+
+// Serializes changes to the pantry file
+var pantryMutex sync.Mutex
+
+// Returns the pantry
+func GetPantry() (pantry.Pantry, error) {
+	pantryMutex.Lock()
+	defer pantryMutex.Unlock()
+
+	return pantry.Load(config.PantryPath())
+}
+
+// Parses a free-form pantry item (e.g. "2 kg flour"), items without a
+// quantity are in stock in an unknown amount.
+func ParsePantryItem(text string) (pantry.Item, error) {
+	ingr := importer.ParseIngredientLine(text)
+	if ingr.Name == "" {
+		return pantry.Item{}, errors.New("Item has no name.")
+	}
+
+	item := pantry.Item{Name: ingr.Name, Qty: cook.TryParseQty(ingr.Qty)}
+	if item.Qty != cook.NoQty {
+		item.Unit = ingr.Unit
+	}
+	return item, nil
+}
+
+// Applies `update` to the pantry and saves the result, returning the updated
+// pantry. Nothing is saved if `update` fails.
+func UpdatePantry(update func(p *pantry.Pantry) error) (pantry.Pantry, error) {
+	var err error
+	path := config.PantryPath()
+
+	pantryMutex.Lock()
+	defer pantryMutex.Unlock()
+
+	var p pantry.Pantry
+	if p, err = pantry.Load(path); err != nil {
+		return pantry.Pantry{}, err
+	}
+	if err = update(&p); err != nil {
+		return pantry.Pantry{}, err
+	}
+	if err = pantry.Save(path, p); err != nil {
+		return pantry.Pantry{}, err
+	}
+	return p, nil
+}
+
+// Adds a free-form item (see `ParsePantryItem`) to the pantry, expiring on
+// `expires` (formatted as `pantry.DateFormat`) if not empty.
+func AddPantryItem(text string, expires string) (pantry.Pantry, error) {
+	item, err := ParsePantryItem(text)
+	if err != nil {
+		return pantry.Pantry{}, err
+	}
+	item.Expires = expires
+
+	return UpdatePantry(func(p *pantry.Pantry) error {
+		return p.Add(item)
+	})
+}
+
+// Removes a free-form item (see `ParsePantryItem`) from the pantry. Items
+// without a quantity remove all stock of that name.
+func RemovePantryItem(text string) (pantry.Pantry, error) {
+	item, err := ParsePantryItem(text)
+	if err != nil {
+		return pantry.Pantry{}, err
+	}
+
+	return UpdatePantry(func(p *pantry.Pantry) error {
+		return p.Remove(item)
+	})
+}
@@ -0,0 +1,111 @@
+package api
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/plan"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// Serializes changes to the meal plan file
+var planMutex sync.Mutex
+
+// Returns the meal plan
+func GetPlan() (plan.Plan, error) {
+	planMutex.Lock()
+	defer planMutex.Unlock()
+
+	return plan.Load(config.PlanPath())
+}
+
+// Applies `update` to the meal plan and saves the result, returning the
+// updated plan. Nothing is saved if `update` fails.
+func UpdatePlan(update func(p *plan.Plan) error) (plan.Plan, error) {
+	var err error
+	path := config.PlanPath()
+
+	planMutex.Lock()
+	defer planMutex.Unlock()
+
+	var p plan.Plan
+	if p, err = plan.Load(path); err != nil {
+		return plan.Plan{}, err
+	}
+	if err = update(&p); err != nil {
+		return plan.Plan{}, err
+	}
+	if err = plan.Save(path, p); err != nil {
+		return plan.Plan{}, err
+	}
+	return p, nil
+}
+
+// Plans a recipe for a meal (see `plan.Plan.Add`).
+//
+// Returns an error if the recipe doesn't exist.
+func AddMeal(meal plan.Meal) (plan.Plan, error) {
+	if _, err := loadRecipe(meal.Recipe); err != nil {
+		return plan.Plan{}, fmt.Errorf("Recipe %v does not exist.", meal.Recipe)
+	}
+
+	return UpdatePlan(func(p *plan.Plan) error {
+		return p.Add(meal)
+	})
+}
+
+// Removes planned meals (see `plan.Plan.Remove`)
+func RemoveMeal(date string, meal string, recipe string) (plan.Plan, error) {
+	return UpdatePlan(func(p *plan.Plan) error {
+		return p.Remove(date, meal, recipe)
+	})
+}
+
+// Returns the meals planned within an ISO 8601 week (e.g. "2026-W43"), along
+// with the Monday it starts on.
+func GetWeekMeals(week string) ([]plan.Meal, time.Time, error) {
+	var err error
+
+	var monday time.Time
+	if monday, err = plan.ParseWeek(week); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var p plan.Plan
+	if p, err = GetPlan(); err != nil {
+		return nil, time.Time{}, err
+	}
+	return p.Between(monday, monday.AddDate(0, 0, 7)), monday, nil
+}
+
+// Returns the shopping list arguments (see `BuildShoppingList`) of every meal
+// planned within an ISO 8601 week.
+//
+// Returns an error if nothing is planned that week.
+func PlanShoppingArgs(week string) ([]string, error) {
+	meals, _, err := GetWeekMeals(week)
+	if err != nil {
+		return nil, err
+	}
+	if len(meals) == 0 {
+		return nil, fmt.Errorf("Nothing is planned in %v.", week)
+	}
+
+	args := make([]string, 0, len(meals))
+	for _, meal := range meals {
+		args = append(args, meal.RecipeArg())
+	}
+	return args, nil
+}
+
+// Builds a shopping list named `name` for everything planned within an
+// ISO 8601 week (see `BuildShoppingList`).
+func BuildPlanShoppingList(name string, week string) (shopping.List, error) {
+	args, err := PlanShoppingArgs(week)
+	if err != nil {
+		return shopping.List{}, err
+	}
+	return BuildShoppingList(name, args)
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/schedule"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+// The cookware only one step may use at a time, unless configured
+var defaultExclusiveCookware = []string{"oven"}
+
+// Schedules the steps of every recipe in `names` (and the recipes they
+// reference) to be ready at `ready`, see `schedule.Backward`.
+//
+// Steps of a recipe follow each other in order, and a step using a referenced
+// recipe waits until it's done. Each step takes as long as its timers, plus
+// its hands-on time, read from the metadata:
+//
+//	>> active time: 10 min
+//	>> step 2 active time: 20 min
+//
+// which is `schedule.DefaultActive` unless given (for every step, or step 2).
+func ScheduleRecipes(names []string, ready time.Time) (schedule.Schedule, error) {
+	if len(names) == 0 {
+		return schedule.Schedule{}, errors.New("No recipes to schedule.")
+	}
+
+	exclusive := map[string]bool{}
+	cookware := config.GetConfig().Schedule.ExclusiveCookware
+	if cookware == nil {
+		cookware = defaultExclusiveCookware
+	}
+	for _, name := range cookware {
+		exclusive[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	b := scheduleBuilder{exclusive: exclusive, last: map[string]int{}}
+	for _, name := range names {
+		if _, err := b.addRecipe(name, []string{}); err != nil {
+			return schedule.Schedule{}, err
+		}
+	}
+	return schedule.Backward(b.tasks, ready)
+}
+
+// Collects the steps of recipes as tasks for `schedule.Backward`
+type scheduleBuilder struct {
+	tasks     []schedule.Task
+	exclusive map[string]bool // Lowercase names of exclusive cookware
+	last      map[string]int  // The last task of each added recipe, -1 if it has none
+}
+
+// Adds the steps of a recipe, after those of the recipes it references (once
+// each). `path` is the chain of recipes which led to `name`, to detect cycles.
+//
+// Returns the index of the recipe's last task, -1 if it has no steps.
+func (b *scheduleBuilder) addRecipe(name string, path []string) (int, error) {
+	if last, ok := b.last[name]; ok {
+		return last, nil
+	}
+	for _, visited := range path {
+		if visited == name {
+			cycle := strings.Join(append(path, name), " -> ")
+			return -1, errors.New("Recipe reference cycle: " + cycle)
+		}
+	}
+	path = append(path, name)
+
+	var err error
+	var r *cook.Recipe
+	if r, err = loadRecipe(name); err != nil {
+		return -1, fmt.Errorf("Failed to load %s: %w", name, err)
+	}
+
+	var active time.Duration
+	var hasActive bool
+	if active, hasActive, err = activeTime(r, "active time"); err != nil {
+		return -1, fmt.Errorf("%s: %w", name, err)
+	}
+	if !hasActive {
+		active = schedule.DefaultActive
+	}
+
+	opts := recipe.RenderOptions{Units: config.GetConfig().Units}
+	last := -1
+	for i, step := range r.Steps {
+		task := schedule.Task{Recipe: name, Step: i + 1, Active: active, After: []int{}}
+		if last >= 0 {
+			task.After = append(task.After, last)
+		}
+
+		var stepActive time.Duration
+		var ok bool
+		key := "step " + strconv.Itoa(i+1) + " active time"
+		if stepActive, ok, err = activeTime(r, key); err != nil {
+			return -1, fmt.Errorf("%s: %w", name, err)
+		} else if ok {
+			task.Active = stepActive
+		}
+
+		manifest := r.StepManifests[i]
+		for _, j := range manifest.Timers {
+			timer := r.Timers[j]
+			if timer.QtyVal == cook.NoQty {
+				continue
+			}
+			if d, err := units.ToDuration(timer.QtyVal, timer.Unit); err == nil {
+				task.Passive += d
+			}
+		}
+		seen := map[string]bool{}
+		for _, j := range manifest.Cookware {
+			cookware := strings.ToLower(strings.TrimSpace(r.Cookware[j].Name))
+			if b.exclusive[cookware] && !seen[cookware] {
+				seen[cookware] = true
+				task.Cookware = append(task.Cookware, cookware)
+			}
+		}
+		for _, j := range manifest.Ingredients {
+			ingr := r.Ingredients[j]
+			if !ingr.IsRecipeRef() {
+				continue
+			}
+			var sub int
+			if sub, err = b.addRecipe(ResolveRecipeRef(name, ingr.Name), path); err != nil {
+				return -1, err
+			}
+			if sub >= 0 {
+				task.After = append(task.After, sub)
+			}
+		}
+
+		var text strings.Builder
+		for _, chunk := range step {
+			if ingr, ok := chunk.(cook.Ingredient); ok && ingr.IsRecipeRef() {
+				text.WriteString(recipe.FilepathToName(ingr.Name))
+				continue
+			}
+			text.WriteString(recipe.ChunkText(chunk, opts))
+		}
+		task.Text = strings.Join(strings.Fields(text.String()), " ")
+
+		b.tasks = append(b.tasks, task)
+		last = len(b.tasks) - 1
+	}
+
+	b.last[name] = last
+	return last, nil
+}
+
+// Reads an active time from the metadata `key`, false if it isn't set
+func activeTime(r *cook.Recipe, key string) (time.Duration, bool, error) {
+	value, ok := r.Metadata.Get(key)
+	if !ok {
+		return 0, false, nil
+	}
+	d, err := units.ParseDuration(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("Invalid %s %q", key, value)
+	}
+	return d, true, nil
+}
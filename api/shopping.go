@@ -0,0 +1,204 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/importer"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/pantry"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// Builds a shopping list named `name` from recipes in the recipes directory.
+//
+// Each recipe is given as `<recipe>[:servings]` (see `shopping.ParseRecipeArg`).
+// Recipes with `servings` metadata are scaled to the servings asked for, others
+// are multiplied by it. Sub-recipes are expanded into their ingredients and
+// every ingredient is combined across recipes, grouped by the aisles of
+// `aisle.conf` (see `shopping.Aisles`). Stock in the pantry is left off.
+func BuildShoppingList(name string, recipeArgs []string) (shopping.List, error) {
+	var err error
+	list := shopping.List{Name: name, Recipes: recipeArgs}
+
+	ingredients := make([]cook.Ingredient, 0)
+	for _, arg := range recipeArgs {
+		var recipeName string
+		var servings float64
+		if recipeName, servings, err = shopping.ParseRecipeArg(arg); err != nil {
+			return shopping.List{}, err
+		}
+
+		var r *cook.Recipe
+		if r, err = loadRecipe(recipeName); err != nil {
+			if errors.Is(err, ErrRecipeNotFound) {
+				return shopping.List{}, fmt.Errorf("Recipe %v does not exist: %w", recipeName, err)
+			}
+			return shopping.List{}, err
+		}
+
+		var expanded []cook.Ingredient
+		if expanded, err = ExpandRecipe(recipeName, servingsFactor(r, servings)); err != nil {
+			return shopping.List{}, err
+		}
+		ingredients = append(ingredients, expanded...)
+	}
+
+	var aisles shopping.Aisles
+	if aisles, err = shopping.LoadAisles(config.AislePath()); err != nil {
+		return shopping.List{}, err
+	}
+
+	var stock pantry.Pantry
+	if stock, err = GetPantry(); err != nil {
+		return shopping.List{}, err
+	}
+
+	list.Items = stock.Subtract(shopping.Combine(ingredients, config.GetConfig().Units, aisles), aisles)
+	for i := range list.Items {
+		list.Items[i].ID = i + 1
+	}
+	list.NextID = len(list.Items) + 1
+	return list, nil
+}
+
+// Returns the factor to scale a recipe by to make `servings`. Recipes with
+// `servings` metadata are scaled to match, others are multiplied by it. Zero
+// servings leaves the recipe as written.
+func servingsFactor(r *cook.Recipe, servings float64) float64 {
+	if servings <= 0 {
+		return 1
+	}
+	if base, ok := cook.RecipeServings(r); ok {
+		return servings / base
+	}
+	return servings
+}
+
+// Serializes changes to saved shopping lists, so concurrent updates (e.g. two
+// phones checking off items) are applied one after another.
+var shoppingMutex sync.Mutex
+
+var ErrListExists = errors.New("Shopping list already exists.")
+
+// Returns the names of every saved shopping list
+func GetShoppingListNames() ([]string, error) {
+	return shopping.Names(config.GetConfig().Shopping.Dir)
+}
+
+// Builds a shopping list from recipes (see `BuildShoppingList`) and saves it
+// into the shopping directory as `name`.
+//
+// Returns ErrListExists if a list of the same name is already saved, and
+// ErrRecipeNotFound if a recipe doesn't exist.
+func CreateShoppingList(name string, recipeArgs []string) (shopping.List, error) {
+	var err error
+	dir := config.GetConfig().Shopping.Dir
+
+	var listPath string
+	if listPath, err = shopping.ListPath(dir, name); err != nil {
+		return shopping.List{}, err
+	}
+
+	var list shopping.List
+	if list, err = BuildShoppingList(name, recipeArgs); err != nil {
+		return shopping.List{}, err
+	}
+
+	shoppingMutex.Lock()
+	defer shoppingMutex.Unlock()
+
+	if common.FileExists(listPath) {
+		return shopping.List{}, ErrListExists
+	}
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return shopping.List{}, err
+	}
+	if err = shopping.Save(dir, list); err != nil {
+		return shopping.List{}, err
+	}
+	return list, nil
+}
+
+// Returns the saved shopping list named `name`
+func GetShoppingList(name string) (shopping.List, error) {
+	shoppingMutex.Lock()
+	defer shoppingMutex.Unlock()
+
+	return shopping.Load(config.GetConfig().Shopping.Dir, name)
+}
+
+// Applies `update` to the saved shopping list named `name` and saves the
+// result, returning the updated list. Nothing is saved if `update` fails.
+//
+// Updates are applied one at a time, each to the latest saved list.
+func UpdateShoppingList(name string, update func(list *shopping.List) error) (shopping.List, error) {
+	var err error
+	dir := config.GetConfig().Shopping.Dir
+
+	shoppingMutex.Lock()
+	defer shoppingMutex.Unlock()
+
+	var list shopping.List
+	if list, err = shopping.Load(dir, name); err != nil {
+		return shopping.List{}, err
+	}
+	if err = update(&list); err != nil {
+		return shopping.List{}, err
+	}
+	if err = shopping.Save(dir, list); err != nil {
+		return shopping.List{}, err
+	}
+	return list, nil
+}
+
+// Parses a free-form item (e.g. "2 litres milk") into an item for a shopping
+// list, in the aisle its name belongs to.
+func ParseShoppingItem(text string) (shopping.Item, error) {
+	var err error
+
+	ingr := importer.ParseIngredientLine(text)
+	if ingr.Name == "" {
+		return shopping.Item{}, errors.New("Item has no name.")
+	}
+
+	var aisles shopping.Aisles
+	if aisles, err = shopping.LoadAisles(config.AislePath()); err != nil {
+		return shopping.Item{}, err
+	}
+
+	item := shopping.Item{Amounts: []shopping.Amount{}}
+	item.Aisle, item.Name = aisles.Lookup(ingr.Name)
+
+	amount := shopping.Amount{Qty: cook.TryParseQty(ingr.Qty), Unit: ingr.Unit}
+	if amount.Qty == cook.NoQty {
+		amount.Text = ingr.Qty
+	}
+	if amount != (shopping.Amount{}) {
+		item.Amounts = append(item.Amounts, amount)
+	}
+	return item, nil
+}
+
+// Deletes the saved shopping list named `name`
+func DeleteShoppingList(name string) error {
+	var err error
+	dir := config.GetConfig().Shopping.Dir
+
+	var listPath string
+	if listPath, err = shopping.ListPath(dir, name); err != nil {
+		return err
+	}
+
+	shoppingMutex.Lock()
+	defer shoppingMutex.Unlock()
+
+	if !common.FileExists(listPath) {
+		return fmt.Errorf("%w: %v", shopping.ErrListNotFound, name)
+	}
+	return os.Remove(listPath)
+}
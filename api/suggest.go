@@ -0,0 +1,99 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/pantry"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// Filters for `SuggestRecipes`
+type SuggestOptions struct {
+	MaxMissing int           // Most ingredients a recipe may be missing, -1 for any
+	Tags       []string      // Tags a recipe must have (all of them)
+	MaxTime    time.Duration // Longest total time of a recipe, 0 for any
+}
+
+// Ranks every recipe by how many of its ingredients are in the pantry (see
+// `pantry.RankSuggestions`), reporting what's missing from each.
+//
+// Sub-recipes are expanded into their ingredients. Optional ingredients (see
+// `recipe.OptionalIngredients`) aren't needed. Recipes without a known total
+// time (see `recipe.TotalTime`) are left out when filtering by time, as are
+// recipes with broken references.
+func SuggestRecipes(opts SuggestOptions) ([]pantry.Suggestion, error) {
+	var err error
+
+	var names []string
+	if names, err = GetAllRecipeNames(); err != nil {
+		return nil, err
+	}
+
+	var stock pantry.Pantry
+	if stock, err = GetPantry(); err != nil {
+		return nil, err
+	}
+
+	var aisles shopping.Aisles
+	if aisles, err = shopping.LoadAisles(config.AislePath()); err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]pantry.Suggestion, 0)
+	for _, name := range names {
+		var r *cook.Recipe
+		if r, err = loadRecipe(name); err != nil {
+			return nil, err
+		}
+		if !hasTags(r, opts.Tags) {
+			continue
+		}
+		total, hasTime := recipe.TotalTime(r)
+		if opts.MaxTime > 0 && (!hasTime || total > opts.MaxTime) {
+			continue
+		}
+
+		var expanded []cook.Ingredient
+		if expanded, err = ExpandRecipe(name, 1); err != nil {
+			// Broken references are reported by `cook check`, not here
+			continue
+		}
+		optional := recipe.OptionalIngredients(r)
+		required := make([]cook.Ingredient, 0, len(expanded))
+		for _, ingr := range expanded {
+			if !optional[strings.ToLower(strings.TrimSpace(ingr.Name))] {
+				required = append(required, ingr)
+			}
+		}
+
+		suggestion := stock.Suggest(name, required, aisles)
+		if opts.MaxMissing >= 0 && len(suggestion.Missing) > opts.MaxMissing {
+			continue
+		}
+		if hasTime {
+			suggestion.Minutes = int(total.Round(time.Minute) / time.Minute)
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	pantry.RankSuggestions(suggestions)
+	return suggestions, nil
+}
+
+// Returns whether a recipe has every one of `tags` (ignoring case)
+func hasTags(r *cook.Recipe, tags []string) bool {
+	have := map[string]bool{}
+	for _, tag := range recipe.Tags(r) {
+		have[tag] = true
+	}
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" && !have[tag] {
+			return false
+		}
+	}
+	return true
+}
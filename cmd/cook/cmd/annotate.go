@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/importer"
+	"github.com/spf13/cobra"
+)
+
+// The name to save an annotated recipe as
+var annotateName string
+
+// A flag to save without confirmation
+var annotateYes bool
+
+var annotateCmd = &cobra.Command{
+	Use:   "annotate <file.txt>",
+	Short: "Converts a plain text recipe into cooklang",
+	Long: `Converts a plain text recipe (a list of ingredients followed by the method) into
+cooklang, then saves it into the recipes folder.
+
+The ingredient list is parsed into quantities and units (e.g. "1/2 tsp salt"), and each
+ingredient is annotated where it is first mentioned in the method. Common cookware and
+durations (e.g. "bake 25 minutes") are annotated as well.
+
+A preview of the changes is shown before saving, use --yes to save without asking.
+The recipe is saved under its title (or the file's name) unless --name is passed,
+which may include folders (e.g. --name sauces/pesto), created as needed.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		path := args[0]
+
+		var data []byte
+		if data, err = os.ReadFile(path); err != nil {
+			errTxt := fmt.Sprintf("Failed to read %v: %v\n", path, err)
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+
+		r := importer.ParsePlainText(string(data))
+		source := importer.ToCooklang(r)
+		fmt.Print(importer.Diff(string(data), source))
+
+		name := annotateName
+		if name == "" && r.Name != "" {
+			name = importer.FileName(r.Name)
+		} else if name == "" {
+			name = importer.FileName(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		}
+
+		if !annotateYes {
+			var answer string
+			fmt.Printf("\nSave as %v? [y/N] ", name)
+			fmt.Scanln(&answer)
+			if answer = strings.ToLower(answer); answer != "y" && answer != "yes" {
+				os.Stderr.WriteString("Aborted.\n")
+				os.Exit(1)
+			}
+		}
+
+		contents := []byte(source)
+		if err = api.CreateRecipe(name, &contents); err != nil {
+			errTxt := fmt.Sprintf("Failed to save %v: %v\n", name, err)
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+		fmt.Printf("Saved as: %v\n", name)
+	},
+}
+
+func init() {
+	annotateCmd.Flags().StringVarP(&annotateName, "name", "n", "",
+		"Recipe to save as, relative to the recipes folder")
+	annotateCmd.Flags().BoolVarP(&annotateYes, "yes", "y", false,
+		"Save without asking for confirmation")
+
+	rootCmd.AddCommand(annotateCmd)
+}
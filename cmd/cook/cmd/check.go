@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"github.com/spf13/cobra"
+)
+
+// A flag to output issues as JSON
+var checkJSON bool
+
+var checkCmd = &cobra.Command{
+	Use:   "check [recipe]...",
+	Short: "Checks recipes for common mistakes",
+	Long: `Checks recipes within the recipes folder for common mistakes, such as unknown units,
+unparsable quantities, empty steps or references to missing recipes.
+
+Every recipe is checked if none are passed. Issues are printed as
+	file:line:col: severity: message [rule]
+
+The severity of each rule can be changed (or turned "off") in the config, e.g.
+	[lint.rules]
+	unknown-unit = "error"
+
+Exits with status 1 if any issue is an error.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		issues := make([]api.LintIssue, 0)
+		if len(args) == 0 {
+			if issues, err = api.LintAllRecipes(); err != nil {
+				errTxt := fmt.Sprintf("Failed to check recipes: %v\n", err)
+				os.Stderr.WriteString(errTxt)
+				os.Exit(1)
+			}
+		}
+		for _, name := range args {
+			var recipeIssues []api.LintIssue
+			if recipeIssues, err = api.LintRecipe(name); err != nil {
+				errTxt := fmt.Sprintf("Failed to check %v: %v\n", name, err)
+				if errors.Is(err, api.ErrRecipeNotFound) {
+					errTxt = fmt.Sprintf("Recipe %v does not exist.\n", name)
+				}
+				os.Stderr.WriteString(errTxt)
+				os.Exit(1)
+			}
+			issues = append(issues, recipeIssues...)
+		}
+
+		if checkJSON {
+			var jsonBytes []byte
+			if jsonBytes, err = json.MarshalIndent(issues, "", "  "); err != nil {
+				panic(err)
+			}
+			fmt.Println(string(jsonBytes))
+		} else {
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+		}
+
+		for _, issue := range issues {
+			if issue.Severity == api.SeverityError {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func init() {
+	checkCmd.Flags().BoolVarP(&checkJSON, "json", "", false, "Output issues as JSON")
+
+	rootCmd.AddCommand(checkCmd)
+}
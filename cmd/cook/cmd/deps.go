@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"github.com/spf13/cobra"
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps <recipe>",
+	Short: "Shows the recipes a recipe uses, is used by and its combined ingredients",
+	Long: `Shows the dependencies of a recipe within the recipes folder.
+
+Recipes can reference other recipes as ingredients, e.g. @./sauces/hollandaise{150%g}.
+This lists the recipes referenced by (uses) and referencing (used by) the passed recipe,
+followed by the ingredients of the recipe with every sub-recipe expanded.`,
+
+	// Print help if no arguments are passed
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		name := args[0]
+
+		var deps api.RecipeDeps
+		if deps, err = api.GetRecipeDeps(name); err != nil {
+			errTxt := fmt.Sprintf("Failed to read %v: %v\n", name, err)
+			if errors.Is(err, api.ErrRecipeNotFound) {
+				errTxt = fmt.Sprintf("Recipe %v does not exist.\n", name)
+			}
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+
+		var ingredients []cook.Ingredient
+		if ingredients, err = api.ExpandRecipe(name, 1); err != nil {
+			errTxt := fmt.Sprintf("Failed to expand %v: %v\n", name, err)
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+
+		fmt.Println("Uses:")
+		for _, dep := range deps.Uses {
+			fmt.Printf("\t%v\n", dep)
+		}
+		fmt.Println("")
+
+		fmt.Println("Used by:")
+		for _, dep := range deps.UsedBy {
+			fmt.Printf("\t%v\n", dep)
+		}
+		fmt.Println("")
+
+		fmt.Println("Ingredients:")
+		wr := new(tabwriter.Writer)
+		wr.Init(os.Stdout, 0, 4, 4, ' ', tabwriter.TabIndent)
+		for _, ingr := range ingredients {
+			fmt.Fprintf(wr, "\t%v\t%v %v\n", ingr.Name, ingr.Qty, ingr.Unit)
+		}
+		wr.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(depsCmd)
+}
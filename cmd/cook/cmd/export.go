@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/export"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+	"github.com/spf13/cobra"
+)
+
+// The output location of an export
+var exportOutput string
+
+// Whether to parse temperatures in exported recipes
+var exportTemperatures bool
+
+// The output file of an EPUB export
+var epubOutput string
+
+// The title of an EPUB export
+var epubTitle string
+
+// Options of book (LaTeX and Typst) exports
+var (
+	bookOutput   string
+	bookTitle    string
+	bookScale    float64
+	bookServings float64
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports recipes into other formats",
+	Long: `Exports a recipe, or a directory of recipes, into other formats.
+
+Recipes are looked up in the recipes folder if they don't exist at the passed location.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var exportHTMLCmd = &cobra.Command{
+	Use:   "html <recipe|dir>",
+	Short: "Exports recipes as standalone HTML pages",
+	Long: `Exports a recipe, or a directory of recipes, as standalone HTML pages.
+
+A single recipe is written to <output>/<recipe>.html. A directory is written to <output>
+with one page per recipe (mirroring its folders) and an index.html linking to each.
+
+The built-in templates (recipe.html, index.html and base.html) can be replaced by setting
+a template directory in the config:
+	[export]
+	template-dir = "/path/to/templates"`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		src := resolveExportSource(args[0])
+		opts := export.HTMLOptions{
+			TemplateDir: config.GetConfig().Export.TemplateDir,
+			Render:      recipe.RenderOptions{Units: config.GetConfig().Units, Temperatures: exportTemperatures},
+		}
+
+		if isDir(src) {
+			err = export.ExportHTMLDir(src, exportOutput, opts)
+		} else {
+			name := strings.TrimSuffix(filepath.Base(src), ".cook")
+			outPath := filepath.Join(exportOutput, name+".html")
+			err = export.ExportHTMLRecipe(src, outPath, opts)
+		}
+
+		if err != nil {
+			errTxt := fmt.Sprintf("Export failed: %v\n", err)
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported to: %v\n", exportOutput)
+	},
+}
+
+var exportEPUBCmd = &cobra.Command{
+	Use:   "epub <recipe|dir>",
+	Short: "Exports recipes as an EPUB cookbook",
+	Long: `Exports a recipe, or a directory of recipes, as an EPUB 3 cookbook.
+
+The book has a page per recipe, a table of contents following the folder hierarchy and an
+ingredient index at the back. Photos sharing a recipe's name (e.g. toast.jpg next to
+toast.cook) are included with their recipe.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		src := resolveExportSource(args[0])
+		opts := export.EPUBOptions{
+			Title:  epubTitle,
+			Render: recipe.RenderOptions{Units: config.GetConfig().Units, Temperatures: exportTemperatures},
+		}
+
+		file, err := os.Create(epubOutput)
+		if err != nil {
+			errTxt := fmt.Sprintf("Failed to create %v: %v\n", epubOutput, err)
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+
+		if err = export.ExportEPUB(src, file, opts); err == nil {
+			err = file.Close()
+		} else {
+			file.Close()
+			os.Remove(epubOutput)
+		}
+
+		if err != nil {
+			errTxt := fmt.Sprintf("Export failed: %v\n", err)
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported to: %v\n", epubOutput)
+	},
+}
+
+// Creates the command exporting books of `format`, named after it (e.g. `latex`)
+func newExportBookCmd(format string, displayName string) *cobra.Command {
+	return &cobra.Command{
+		Use:   format + " <recipe|dir>",
+		Short: fmt.Sprintf("Exports recipes as a %v book", displayName),
+		Long: fmt.Sprintf(`Exports a recipe, or a directory of recipes, as %v source for a printed book.
+
+A directory becomes one document with a table of contents and a chapter per folder. Each
+recipe has a header of its metadata, with its ingredients beside its steps. A lone recipe
+becomes a standalone document.
+
+Quantities can be scaled with --scale, or to a number of servings with --servings (for
+recipes with servings metadata).`, displayName),
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				cmd.Help()
+				os.Exit(0)
+			}
+
+			initConfig()
+		},
+
+		Run: func(cmd *cobra.Command, args []string) {
+			src := resolveExportSource(args[0])
+			opts := export.BookOptions{
+				Format:   format,
+				Title:    bookTitle,
+				Scale:    bookScale,
+				Servings: bookServings,
+				Render:   recipe.RenderOptions{Units: config.GetConfig().Units, Temperatures: exportTemperatures},
+			}
+
+			output := bookOutput
+			if output == "" {
+				output = "cookbook." + map[string]string{
+					export.BookLaTeX: "tex",
+					export.BookTypst: "typ",
+				}[format]
+			}
+
+			file, err := os.Create(output)
+			if err != nil {
+				errTxt := fmt.Sprintf("Failed to create %v: %v\n", output, err)
+				os.Stderr.WriteString(errTxt)
+				os.Exit(1)
+			}
+
+			if err = export.ExportBook(src, file, opts); err == nil {
+				err = file.Close()
+			} else {
+				file.Close()
+				os.Remove(output)
+			}
+
+			if err != nil {
+				errTxt := fmt.Sprintf("Export failed: %v\n", err)
+				os.Stderr.WriteString(errTxt)
+				os.Exit(1)
+			}
+			fmt.Printf("Exported to: %v\n", output)
+		},
+	}
+}
+
+// Finds the file or directory to export, checking the recipes dir if `path`
+// doesn't exist locally. Exits on failure.
+func resolveExportSource(path string) string {
+	if common.FileExists(path) {
+		return path
+	}
+
+	// Try the recipes dir, as a folder then as a recipe
+	newPath := filepath.Join(config.GetConfig().Recipe.Dir, path)
+	if common.FileExists(newPath) {
+		return newPath
+	}
+	if filepath.Ext(newPath) == "" && common.FileExists(newPath+".cook") {
+		return newPath + ".cook"
+	}
+
+	errTxt := fmt.Sprintf("Recipe %v does not exist.\n", path)
+	os.Stderr.WriteString(errTxt)
+	os.Exit(1)
+	return ""
+}
+
+// Returns whether `path` is a directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func init() {
+	exportCmd.PersistentFlags().BoolVar(&exportTemperatures, "temperatures", false,
+		"Parses temperatures in step text, showing them in both scales (or the configured units)")
+
+	exportHTMLCmd.Flags().StringVarP(&exportOutput, "output", "o", "html",
+		"Directory to write the pages into")
+
+	exportEPUBCmd.Flags().StringVarP(&epubOutput, "output", "o", "cookbook.epub",
+		"File to write the book into")
+	exportEPUBCmd.Flags().StringVarP(&epubTitle, "title", "t", "",
+		"Title of the book (defaults to the directory's name)")
+
+	for _, bookCmd := range []*cobra.Command{
+		newExportBookCmd(export.BookLaTeX, "LaTeX"),
+		newExportBookCmd(export.BookTypst, "Typst"),
+	} {
+		bookCmd.Flags().StringVarP(&bookOutput, "output", "o", "",
+			"File to write the book into (defaults to cookbook.tex or cookbook.typ)")
+		bookCmd.Flags().StringVarP(&bookTitle, "title", "t", "",
+			"Title of the book (defaults to the directory's name)")
+		bookCmd.Flags().Float64Var(&bookScale, "scale", 0, "Multiplies every quantity")
+		bookCmd.Flags().Float64Var(&bookServings, "servings", 0,
+			"Scales each recipe to serve this many")
+		exportCmd.AddCommand(bookCmd)
+	}
+
+	exportCmd.AddCommand(exportHTMLCmd)
+	exportCmd.AddCommand(exportEPUBCmd)
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/importer"
+	"github.com/spf13/cobra"
+)
+
+// The name to save an imported recipe as
+var importName string
+
+// A flag to print an imported recipe rather than save it
+var importPrint bool
+
+// The format being imported from
+var importFrom string
+
+// How categories of imported archives are kept, "folders" or "tags"
+var importCategories string
+
+var importCmd = &cobra.Command{
+	Use:   "import <file.html|file.json|archive>",
+	Short: "Imports recipes from web pages and other recipe apps",
+	Long: `Imports a recipe from a saved web page (or a JSON-LD file), using its embedded
+schema.org Recipe data, and saves it as cooklang into the recipes folder.
+
+Ingredients are annotated where they are first mentioned within the instructions, and
+the source, servings, times and tags are kept as metadata. The recipe is saved under
+its own name unless --name is passed, e.g. --name sauces/hollandaise
+
+Exports of other recipe apps are imported with --from:
+	paprika    a .paprikarecipes export
+	mealie     a recipe's JSON, or a zip of them
+
+Each recipe's category becomes its folder (or tags, with --categories tags) and photos
+are saved next to their recipe. Recipes which couldn't be imported are listed at the end.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		switch importFrom {
+		case "jsonld":
+			importJSONLD(args[0])
+		case "paprika", "mealie":
+			importArchive(args[0])
+		default:
+			errTxt := fmt.Sprintf("Unknown format %q, expected one of: jsonld, paprika, mealie\n",
+				importFrom)
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+	},
+}
+
+// Imports a single recipe from a web page or JSON-LD file
+func importJSONLD(path string) {
+	var err error
+
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		errTxt := fmt.Sprintf("Failed to read %v: %v\n", path, err)
+		os.Stderr.WriteString(errTxt)
+		os.Exit(1)
+	}
+
+	var r importer.Recipe
+	if r, err = importer.ParseJSONLD(data); err != nil {
+		errTxt := fmt.Sprintf("Failed to import %v: %v\n", path, err)
+		os.Stderr.WriteString(errTxt)
+		os.Exit(1)
+	}
+
+	source := []byte(importer.ToCooklang(r))
+	if importPrint {
+		os.Stdout.Write(source)
+		return
+	}
+
+	name := importName
+	if name == "" {
+		name = importer.FileName(r.Name)
+	}
+	if err = api.CreateRecipe(name, &source); err != nil {
+		errTxt := fmt.Sprintf("Failed to save %v: %v\n", name, err)
+		os.Stderr.WriteString(errTxt)
+		os.Exit(1)
+	}
+	fmt.Printf("Imported %v as: %v\n", r.Name, name)
+}
+
+// Imports every recipe in another app's export
+func importArchive(path string) {
+	var err error
+
+	if importCategories != "folders" && importCategories != "tags" {
+		errTxt := fmt.Sprintf("Unknown --categories %q, expected folders or tags\n", importCategories)
+		os.Stderr.WriteString(errTxt)
+		os.Exit(1)
+	}
+
+	var recipes []importer.ArchivedRecipe
+	var failures []importer.Failure
+	if importFrom == "paprika" {
+		recipes, failures, err = importer.ParsePaprika(path)
+	} else {
+		recipes, failures, err = importer.ParseMealie(path)
+	}
+	if err != nil {
+		errTxt := fmt.Sprintf("Failed to read %v: %v\n", path, err)
+		os.Stderr.WriteString(errTxt)
+		os.Exit(1)
+	}
+
+	report := api.ImportRecipes(recipes, importCategories == "folders")
+	report.Failed = append(failures, report.Failed...)
+
+	for _, name := range report.Imported {
+		fmt.Printf("Imported: %v\n", name)
+	}
+	fmt.Printf("\n%v imported, %v failed\n", len(report.Imported), len(report.Failed))
+	if len(report.Failed) > 0 {
+		fmt.Println("\nFailed:")
+		for _, failure := range report.Failed {
+			fmt.Printf("\t%v: %v\n", failure.Name, failure.Reason)
+		}
+		os.Exit(1)
+	}
+}
+
+func init() {
+	importCmd.Flags().StringVarP(&importName, "name", "n", "",
+		"Recipe to save as, relative to the recipes folder")
+	importCmd.Flags().BoolVarP(&importPrint, "print", "p", false,
+		"Print the recipe instead of saving it")
+	importCmd.Flags().StringVar(&importFrom, "from", "jsonld",
+		"Format to import from: jsonld, paprika or mealie")
+	importCmd.Flags().StringVar(&importCategories, "categories", "folders",
+		"Keep categories of imported archives as folders or tags")
+
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/pantry"
+	"github.com/spf13/cobra"
+)
+
+// The expiry date of added pantry items
+var pantryExpires string
+
+// How many days ahead `pantry expiring` looks
+var pantryDays int
+
+var pantryCmd = &cobra.Command{
+	Use:   "pantry",
+	Short: "Tracks the ingredients kept at home",
+	Long: `Tracks the ingredients kept at home, which are left off shopping lists.
+
+Items are written as in a recipe's ingredient list, e.g. "2 kg flour" or "500 ml milk". Items
+without a quantity (e.g. "salt") are assumed to always be in stock. The pantry is stored in
+pantry.toml, alongside the shopping folder unless set in the config:
+	pantry = "/path/to/pantry.toml"`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var pantryAddCmd = &cobra.Command{
+	Use:   "add <item>",
+	Short: "Adds an item to the pantry",
+	Long: `Adds an item to the pantry, e.g. cook pantry add 2 kg flour --expires 2026-12-01
+
+Items of the same name in compatible units are summed together.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		var p pantry.Pantry
+		if p, err = api.AddPantryItem(strings.Join(args, " "), pantryExpires); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to add to pantry: %v\n", err))
+			os.Exit(1)
+		}
+		printPantry(p.Items)
+	},
+}
+
+var pantryRemoveCmd = &cobra.Command{
+	Use:   "remove <item>",
+	Short: "Removes an item from the pantry",
+	Long: `Removes an item from the pantry. With a quantity, only that much is taken from stock
+(e.g. cook pantry remove 500 g flour), otherwise every item of that name is removed.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		var p pantry.Pantry
+		if p, err = api.RemovePantryItem(strings.Join(args, " ")); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to remove from pantry: %v\n", err))
+			os.Exit(1)
+		}
+		printPantry(p.Items)
+	},
+}
+
+var pantryListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the items in the pantry",
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		var p pantry.Pantry
+		if p, err = api.GetPantry(); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to read pantry: %v\n", err))
+			os.Exit(1)
+		}
+		printPantry(p.Items)
+	},
+}
+
+var pantryExpiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "Lists pantry items which expire soon",
+	Long:  `Lists pantry items which expire within --days days (or already have), soonest first.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		var p pantry.Pantry
+		if p, err = api.GetPantry(); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to read pantry: %v\n", err))
+			os.Exit(1)
+		}
+		printPantry(p.Expiring(time.Now(), pantryDays))
+	},
+}
+
+// Prints each pantry item alongside its amount and expiry date
+func printPantry(items []pantry.Item) {
+	if len(items) == 0 {
+		fmt.Println("Nothing in the pantry.")
+		return
+	}
+
+	wr := tabwriter.NewWriter(os.Stdout, 0, 4, 4, ' ', 0)
+	for _, item := range items {
+		expires := ""
+		if item.Expires != "" {
+			expires = "expires " + item.Expires
+		}
+		fmt.Fprintf(wr, "%v\t%v\t%v\n", item.Name, item.AmountText(), expires)
+	}
+	wr.Flush()
+}
+
+func init() {
+	pantryAddCmd.Flags().StringVarP(&pantryExpires, "expires", "e", "",
+		"The date the item expires, e.g. 2026-12-01")
+	pantryExpiringCmd.Flags().IntVarP(&pantryDays, "days", "d", 7,
+		"How many days ahead to look")
+
+	pantryCmd.AddCommand(pantryAddCmd)
+	pantryCmd.AddCommand(pantryRemoveCmd)
+	pantryCmd.AddCommand(pantryListCmd)
+	pantryCmd.AddCommand(pantryExpiringCmd)
+	rootCmd.AddCommand(pantryCmd)
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/plan"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+	"github.com/spf13/cobra"
+)
+
+// The ISO 8601 week shown or shopped for, the current week if empty
+var planWeek string
+
+// The meal planned for (or removed from)
+var planMeal string
+
+// The output file of `plan export-ics`
+var planICSOutput string
+
+// Options of `plan shopping-list`, see `shoppingListCmd`
+var (
+	planShoppingSave  string
+	planShoppingForce bool
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Plans meals a week at a time",
+	Long: `Plans recipes for the meals of each day.
+
+The plan is stored in plan.toml, alongside the shopping folder unless set in the config:
+	plan = "/path/to/plan.toml"
+
+Dates are written as 2026-10-19, today, tomorrow or a weekday (the next one, e.g. friday).
+Weeks are ISO 8601 weeks, e.g. 2026-W43.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var planShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Shows the meals planned for a week",
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		week := resolvePlanWeek()
+		meals, monday, err := api.GetWeekMeals(week)
+		if err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to read plan: %v\n", err))
+			os.Exit(1)
+		}
+
+		fmt.Printf("%v (%v - %v)\n", week,
+			monday.Format("Jan 2"), monday.AddDate(0, 0, 6).Format("Jan 2"))
+		if len(meals) == 0 {
+			fmt.Println("Nothing planned.")
+			return
+		}
+
+		wr := tabwriter.NewWriter(os.Stdout, 0, 4, 4, ' ', 0)
+		lastDate := ""
+		for _, meal := range meals {
+			day := ""
+			if meal.Date != lastDate {
+				date, _ := time.Parse(plan.DateFormat, meal.Date)
+				day = date.Format("Mon 2006-01-02")
+				lastDate = meal.Date
+			}
+			servings := ""
+			if meal.Servings != cook.NoQty {
+				servings = fmt.Sprintf("(%v servings)", cook.FormatQty(meal.Servings))
+			}
+			fmt.Fprintf(wr, "%v\t%v\t%v\t%v\n", day, meal.Meal, meal.Recipe, servings)
+		}
+		wr.Flush()
+	},
+}
+
+var planAddCmd = &cobra.Command{
+	Use:   "add <date> <recipe>[:servings]...",
+	Short: "Plans recipes for a meal",
+	Long: `Plans recipes for a meal (dinner unless set with --meal), e.g.
+	cook plan add friday mains/lasagna:4 sides/salad`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) < 2 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		date := resolvePlanDate(args[0])
+		for _, arg := range args[1:] {
+			name, servings, err := shopping.ParseRecipeArg(arg)
+			if err != nil {
+				os.Stderr.WriteString(err.Error() + "\n")
+				os.Exit(1)
+			}
+
+			meal := plan.Meal{Date: date, Meal: planMeal, Recipe: name, Servings: servings}
+			if _, err = api.AddMeal(meal); err != nil {
+				os.Stderr.WriteString(fmt.Sprintf("Failed to plan %v: %v\n", name, err))
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+var planRemoveCmd = &cobra.Command{
+	Use:   "remove <date> [recipe]",
+	Short: "Removes planned meals",
+	Long: `Removes the recipes planned on a date, only those of --meal if set, and only [recipe]
+if given.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 || len(args) > 2 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		date := resolvePlanDate(args[0])
+		recipe := ""
+		if len(args) == 2 {
+			recipe = args[1]
+		}
+
+		if _, err := api.RemoveMeal(date, planMeal, recipe); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to remove from plan: %v\n", err))
+			os.Exit(1)
+		}
+	},
+}
+
+var planShoppingListCmd = &cobra.Command{
+	Use:   "shopping-list",
+	Short: "Builds a shopping list for a week of meals",
+	Long: `Builds a combined shopping list for everything planned in a week, as with
+cook shopping-list. The list is printed, or saved into the shopping folder with --save <name>.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		list, err := api.BuildPlanShoppingList(planShoppingSave, resolvePlanWeek())
+		if err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to build shopping list: %v\n", err))
+			os.Exit(1)
+		}
+		outputShoppingList(list, planShoppingForce)
+	},
+}
+
+var planExportICSCmd = &cobra.Command{
+	Use:   "export-ics",
+	Short: "Exports the meal plan as an iCalendar file",
+	Long: `Exports every planned meal as an iCalendar (.ics) file, as served to calendar apps by
+cook server (see cook plan feed-url).
+
+Each meal is an event listing its ingredients and linking to the recipe in the webapp. Meal
+times, their length and the webapp's address are set in the config:
+	[calendar]
+	url = "http://192.168.1.10:6969"
+	duration = "1 hour"
+
+	[calendar.meal-times]
+	dinner = "18:30"`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		var file *os.File
+		if file, err = os.Create(planICSOutput); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to create %v: %v\n", planICSOutput, err))
+			os.Exit(1)
+		}
+		defer file.Close()
+
+		if err = api.WritePlanICS(file, planBaseURL()); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to export plan: %v\n", err))
+			os.Exit(1)
+		}
+		fmt.Printf("Exported to: %v\n", planICSOutput)
+	},
+}
+
+var planFeedURLCmd = &cobra.Command{
+	Use:   "feed-url",
+	Short: "Prints the address of the meal plan's calendar feed",
+	Long: `Prints the address calendar apps can subscribe to for the meal plan, served by cook server.
+
+The address holds a token signed with the config's hmac-key, changing the key revokes it.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		token, err := api.PlanFeedToken()
+		if err != nil {
+			os.Stderr.WriteString(err.Error() + "\n")
+			os.Exit(1)
+		}
+		fmt.Printf("%v/api/0/plan.ics?token=%v\n", planBaseURL(), token)
+	},
+}
+
+// Returns the webapp's address, as configured or the local server's
+func planBaseURL() string {
+	if baseURL := config.GetConfig().Calendar.URL; baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+	return "http://localhost:6969"
+}
+
+// Returns the week passed by --week, or the current week
+func resolvePlanWeek() string {
+	if planWeek == "" {
+		return plan.FormatWeek(time.Now())
+	}
+	if _, err := plan.ParseWeek(planWeek); err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		os.Exit(1)
+	}
+	return planWeek
+}
+
+// Parses a date argument, exiting on failure
+func resolvePlanDate(arg string) string {
+	date, err := plan.ParseDate(arg, time.Now())
+	if err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		os.Exit(1)
+	}
+	return date
+}
+
+func init() {
+	planShowCmd.Flags().StringVarP(&planWeek, "week", "w", "",
+		"The week to show, e.g. 2026-W43 (defaults to this week)")
+	planAddCmd.Flags().StringVarP(&planMeal, "meal", "m", plan.DefaultMeal,
+		"The meal to plan for, e.g. lunch")
+	planRemoveCmd.Flags().StringVarP(&planMeal, "meal", "m", "",
+		"Only removes recipes from this meal")
+	planShoppingListCmd.Flags().StringVarP(&planWeek, "week", "w", "",
+		"The week to shop for, e.g. 2026-W43 (defaults to this week)")
+	planShoppingListCmd.Flags().StringVarP(&planShoppingSave, "save", "s", "",
+		"Saves the list into the shopping folder under this name")
+	planShoppingListCmd.Flags().BoolVarP(&planShoppingForce, "force", "f", false,
+		"Replaces an existing list of the same name")
+	planExportICSCmd.Flags().StringVarP(&planICSOutput, "output", "o", "plan.ics",
+		"The file to export to")
+
+	planCmd.AddCommand(planShowCmd)
+	planCmd.AddCommand(planAddCmd)
+	planCmd.AddCommand(planRemoveCmd)
+	planCmd.AddCommand(planShoppingListCmd)
+	planCmd.AddCommand(planExportICSCmd)
+	planCmd.AddCommand(planFeedURLCmd)
+	rootCmd.AddCommand(planCmd)
+}
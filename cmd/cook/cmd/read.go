@@ -2,23 +2,57 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
 	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/api"
 	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
 	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/nutrition"
 	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
 	"github.com/spf13/cobra"
 )
 
+// The format recipes are printed in
+var readFormat string
+
+// Whether to print the nutrition of recipes
+var readNutrition bool
+
+// Whether to parse temperatures in recipes
+var readTemperatures bool
+
 var readCmd = &cobra.Command{
 	Use:   "read",
 	Short: "Parses a recipe file and pretty prints it to stdout",
 	Long: `Parses a .cook file and prints it to stdout. 
 
 If the file does not exist at the passed location, the recipes folder will be searched
-for it.`,
+for it.
+
+The output format can be chosen with --format, e.g. --format md
+
+With --nutrition, the calories, protein, fat, carbs and fibre of the recipe (in total and per
+serving) are printed after it, or included as "nutrition" in the JSON formats. These are calculated from a nutrient table, nutrition.csv
+alongside the config file, with a header row and values per 100 g, e.g. as exported from
+USDA FoodData Central:
+	description,Energy (kcal),Protein (g),Total lipid (fat) (g),Carbohydrate (g),Fiber (g),density,piece
+	"Egg, whole, raw",143,12.56,9.51,0.72,0,,50
+
+density (g/ml) and piece (the weight of one, in g) convert volumes and counts into weights.
+Values can be changed (or foods added) in nutrition-overrides.csv, and ingredient names are
+mapped to foods in nutrition-aliases.conf, one food per line followed by its aliases:
+	Egg, whole, raw|egg|eggs
+
+These paths can be set in the config:
+	[nutrition]
+	table = "/path/to/nutrition.csv"
+	overrides = "/path/to/nutrition-overrides.csv"
+	aliases = "/path/to/nutrition-aliases.conf"`,
 
 	// Print help if no arguments are passed
 	PreRun: func(cmd *cobra.Command, args []string) {
@@ -31,10 +65,14 @@ for it.`,
 	},
 
 	Run: func(cmd *cobra.Command, args []string) {
+		// Check the format before printing anything
+		if _, err := recipe.GetRenderer(readFormat); err != nil {
+			os.Stderr.WriteString(err.Error() + "\n")
+			os.Exit(1)
+		}
+
 		recipeDir := config.GetConfig().Recipe.Dir
 		for _, path := range args {
-			var r cook.Recipe
-
 			// If not a local file, check recipes dir
 			if !common.FileExists(path) {
 				// Rebuild path
@@ -53,17 +91,108 @@ for it.`,
 				path = newPath
 			}
 
-			// Read file to bytes, parse and print
-			data, err := os.ReadFile(path)
-			if err != nil {
-				panic(err)
+			opts := recipe.RenderOptions{Units: config.GetConfig().Units, Temperatures: readTemperatures}
+			if err := readRecipe(os.Stdout, path, readFormat, opts, readNutrition); err != nil {
+				os.Stderr.WriteString(err.Error() + "\n")
+				os.Exit(1)
 			}
-			r = cook.ParseRecipe(recipe.FilepathToName(path), &data)
-			recipe.PrettyPrint(&r)
 		}
 	},
 }
 
+// Formats which include the nutrition in their output, see `recipe.RenderOptions`.
+// Other formats have it printed after the recipe as a table.
+var nutritionFormats = map[string]bool{"json": true, "jsonld": true, "canonical-json": true}
+
+// Parses the recipe at `path` and writes it to `w` in `format`, along with its
+// nutrition if `withNutrition` is set.
+func readRecipe(w io.Writer, path string, format string, opts recipe.RenderOptions, withNutrition bool) error {
+	renderer, err := recipe.GetRenderer(format)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	r := cook.ParseRecipe(recipe.FilepathToName(path), &data)
+	if opts.Temperatures {
+		cook.ParseTemperatures(&r)
+	}
+
+	var facts nutrition.Facts
+	if withNutrition {
+		// Recipes outside of the recipes dir can't reference others
+		name := r.Name
+		recipeDir := config.GetConfig().Recipe.Dir
+		if rel, err := filepath.Rel(recipeDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			name = strings.TrimSuffix(filepath.ToSlash(rel), filepath.Ext(rel))
+		}
+
+		if facts, err = api.RecipeNutrition(name, &r); err != nil {
+			return fmt.Errorf("Failed to calculate nutrition: %w", err)
+		}
+		if nutritionFormats[format] {
+			opts.Nutrition = &facts
+		}
+	}
+
+	if err = renderer.Render(w, &r, opts); err != nil {
+		return err
+	}
+	if withNutrition && !nutritionFormats[format] {
+		printNutrition(w, facts)
+	}
+	return nil
+}
+
+// Prints the nutrition of a recipe as a table, followed by the ingredients
+// which weren't counted.
+func printNutrition(w io.Writer, facts nutrition.Facts) {
+	fmt.Fprintln(w)
+	wr := tabwriter.NewWriter(w, 0, 4, 3, ' ', 0)
+	header := []string{"Nutrition", "Total"}
+	if facts.PerServing != nil {
+		header = append(header, fmt.Sprintf("Per serving (of %v)", cook.FormatQty(facts.Servings)))
+	}
+	fmt.Fprintln(wr, strings.Join(header, "\t"))
+
+	rows := []struct {
+		name   string
+		format string
+		value  func(n nutrition.Nutrients) float64
+	}{
+		{"Calories", "%.0f kcal", func(n nutrition.Nutrients) float64 { return n.Calories }},
+		{"Protein", "%.1f g", func(n nutrition.Nutrients) float64 { return n.Protein }},
+		{"Fat", "%.1f g", func(n nutrition.Nutrients) float64 { return n.Fat }},
+		{"Carbs", "%.1f g", func(n nutrition.Nutrients) float64 { return n.Carbs }},
+		{"Fibre", "%.1f g", func(n nutrition.Nutrients) float64 { return n.Fibre }},
+	}
+	for _, row := range rows {
+		cells := []string{row.name, fmt.Sprintf(row.format, row.value(facts.Total))}
+		if facts.PerServing != nil {
+			cells = append(cells, fmt.Sprintf(row.format, row.value(*facts.PerServing)))
+		}
+		fmt.Fprintln(wr, strings.Join(cells, "\t"))
+	}
+	wr.Flush()
+
+	if len(facts.Unmatched) > 0 {
+		fmt.Fprintln(w, "\nNot counted:")
+		for _, unmatched := range facts.Unmatched {
+			fmt.Fprintf(w, "  %v (%v)\n", unmatched.Name, unmatched.Reason)
+		}
+	}
+}
+
 func init() {
+	readCmd.Flags().StringVarP(&readFormat, "format", "f", "text",
+		fmt.Sprintf("Output format (%v)", strings.Join(recipe.Formats(), "|")))
+	readCmd.Flags().BoolVarP(&readNutrition, "nutrition", "n", false,
+		"Also prints the recipe's nutrition")
+	readCmd.Flags().BoolVarP(&readTemperatures, "temperatures", "t", false,
+		"Parses temperatures in step text, showing them in both scales (or the configured units)")
+
 	rootCmd.AddCommand(readCmd)
 }
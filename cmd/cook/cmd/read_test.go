@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+	"github.com/BurntSushi/toml"
+)
+
+// Loads a config with a nutrient table and a single recipe, "toast", returning
+// the recipe's path.
+func loadReadConfig(t *testing.T) string {
+	dir := t.TempDir()
+	conf := config.Config{
+		Recipe:   config.RecipeConfig{Dir: filepath.Join(dir, "recipes")},
+		Shopping: config.ShoppingConfig{Dir: filepath.Join(dir, "shopping")},
+	}
+	if err := os.MkdirAll(conf.Recipe.Dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(conf.Recipe.Dir, "toast.cook")
+	files := map[string]string{
+		path:                                ">> servings: 2\nToast @bread{100%g}.\n",
+		filepath.Join(dir, "nutrition.csv"): "name,calories,protein\nBread,250,9\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(name, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	configPath := filepath.Join(dir, "config.toml")
+	file, err := os.Create(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err = toml.NewEncoder(file).Encode(conf); err != nil {
+		t.Fatal(err)
+	}
+	if !config.LoadConfig(configPath) {
+		t.Fatal("Failed to load test config")
+	}
+	return path
+}
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestReadRecipeNutrition(t *testing.T) {
+	path := loadReadConfig(t)
+
+	// JSON formats stay valid JSON, with the nutrition inside them
+	for format, want := range map[string]string{
+		"json":           `"calories": 250`,
+		"jsonld":         `"calories": "125 kcal"`,
+		"canonical-json": `"calories": 250`,
+	} {
+		var buf bytes.Buffer
+		if err := readRecipe(&buf, path, format, recipe.RenderOptions{}, true); err != nil {
+			t.Fatalf("Failed to read as %v: %v", format, err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("Invalid JSON as %v: %v\n%v", format, err, buf.String())
+		}
+		if _, ok := got["nutrition"]; !ok || !strings.Contains(buf.String(), want) {
+			t.Fatalf("Wrong nutrition as %v\ngot:\n%v\nwant: %v.", format, buf.String(), want)
+		}
+	}
+
+	// Other formats have it printed after the recipe
+	var buf bytes.Buffer
+	if err := readRecipe(&buf, path, "text", recipe.RenderOptions{}, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Calories    250 kcal   125 kcal") {
+		t.Fatalf("Missing nutrition table\ngot:\n%v.", buf.String())
+	}
+}
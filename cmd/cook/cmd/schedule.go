@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/schedule"
+	"github.com/spf13/cobra"
+)
+
+// The time everything should be ready at, e.g. "19:00"
+var scheduleReady string
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule --ready <time> <recipe>...",
+	Short: "Plans when to start each step so recipes are ready together",
+	Long: `Plans when to start each step of the recipes (and the recipes they use), working backwards
+so that everything is ready at --ready, e.g.
+	cook schedule --ready 19:00 mains/lasagna sides/salad desserts/tiramisu
+
+Timers run on their own while you work on other steps. Each step also takes 5 minutes of
+hands-on time, unless set in the recipe's metadata (for every step, or just step 2):
+	>> active time: 10 min
+	>> step 2 active time: 20 min
+
+Only one step at a time can use the oven, other cookware can be shared out in the config:
+	[schedule]
+	exclusive-cookware = ["oven", "stand mixer"]`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 || scheduleReady == "" {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		var ready time.Time
+		if ready, err = schedule.ParseReady(scheduleReady, time.Now()); err != nil {
+			os.Stderr.WriteString(err.Error() + "\n")
+			os.Exit(1)
+		}
+
+		var s schedule.Schedule
+		if s, err = api.ScheduleRecipes(args, ready); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to schedule recipes: %v\n", err))
+			os.Exit(1)
+		}
+
+		fmt.Printf("Start at %v to be ready at %v.\n\n", s.Start.Format("15:04"), s.Ready.Format("15:04"))
+		wr := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, entry := range s.Entries {
+			notes := make([]string, 0)
+			if entry.HandsFree.Before(entry.End) {
+				notes = append(notes, "hands-free from "+entry.HandsFree.Format("15:04"))
+			}
+			if len(entry.Cookware) > 0 {
+				notes = append(notes, "uses "+strings.Join(entry.Cookware, ", "))
+			}
+			note := ""
+			if len(notes) > 0 {
+				note = "(" + strings.Join(notes, "; ") + ")"
+			}
+			fmt.Fprintf(wr, "%v-%v\t%v #%v\t%v\t%v\n", entry.Start.Format("15:04"), entry.End.Format("15:04"),
+				entry.Recipe, entry.Step, entry.Text, note)
+		}
+		wr.Flush()
+	},
+}
+
+func init() {
+	scheduleCmd.Flags().StringVarP(&scheduleReady, "ready", "r", "",
+		"When everything should be ready, e.g. 19:00 or \"2026-10-20 19:00\"")
+
+	rootCmd.AddCommand(scheduleCmd)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+	"github.com/spf13/cobra"
+)
+
+// The name to save a shopping list as, printed if empty
+var shoppingSave string
+
+// Whether saving a list may replace an existing one
+var shoppingForce bool
+
+var shoppingListCmd = &cobra.Command{
+	Use:   "shopping-list <recipe>[:servings]...",
+	Short: "Builds a shopping list from recipes",
+	Long: `Builds a combined shopping list from recipes within the recipes folder.
+
+Each recipe can be followed by the servings to shop for, e.g. breakfast/pancakes:4. Recipes
+with servings metadata are scaled to match, others are multiplied by it. Sub-recipes are
+expanded into their ingredients, and ingredients are combined across every recipe (summing
+quantities of compatible units).
+
+Items are grouped by the aisles configured in aisle.conf (next to the config file), where
+names on the same line are synonyms merged into the first:
+	[produce]
+	scallions|green onions
+	tomatoes
+
+	[dairy]
+	milk
+
+Items in no aisle are listed under [other]. Stock in the pantry (see cook pantry) is left
+off the list. The list is printed, or saved into the shopping folder with --save <name>.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		var list shopping.List
+		if list, err = api.BuildShoppingList(shoppingSave, args); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to build shopping list: %v\n", err))
+			os.Exit(1)
+		}
+
+		outputShoppingList(list, shoppingForce)
+	},
+}
+
+// Prints a list, or saves it into the shopping folder if it is named. Existing
+// lists are only replaced if `force` is set.
+func outputShoppingList(list shopping.List, force bool) {
+	var err error
+	if list.Name == "" {
+		printShoppingList(list)
+		return
+	}
+
+	dir := config.GetConfig().Shopping.Dir
+	var listPath string
+	if listPath, err = shopping.ListPath(dir, list.Name); err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		os.Exit(1)
+	}
+	if common.FileExists(listPath) && !force {
+		errTxt := fmt.Sprintf("Shopping list %v already exists, use --force to replace it.\n", list.Name)
+		os.Stderr.WriteString(errTxt)
+		os.Exit(1)
+	}
+
+	if err = shopping.Save(dir, list); err != nil {
+		os.Stderr.WriteString(fmt.Sprintf("Failed to save shopping list: %v\n", err))
+		os.Exit(1)
+	}
+	fmt.Printf("Saved to: %v\n", listPath)
+}
+
+// Prints each item of a list alongside its amounts, grouped by aisle
+func printShoppingList(list shopping.List) {
+	groups := shopping.GroupByAisle(list.Items)
+	for i, group := range groups {
+		// Aisles are only worth showing if some are configured
+		if len(groups) > 1 || group.Aisle != shopping.OtherAisle {
+			if i > 0 {
+				fmt.Println("")
+			}
+			fmt.Printf("[%v]\n", group.Aisle)
+		}
+
+		wr := tabwriter.NewWriter(os.Stdout, 0, 4, 4, ' ', 0)
+		for _, item := range group.Items {
+			fmt.Fprintf(wr, "%v\t%v\n", item.Name, item.AmountText())
+		}
+		wr.Flush()
+	}
+}
+
+func init() {
+	shoppingListCmd.Flags().StringVarP(&shoppingSave, "save", "s", "",
+		"Saves the list into the shopping folder under this name")
+	shoppingListCmd.Flags().BoolVarP(&shoppingForce, "force", "f", false,
+		"Replaces an existing list of the same name")
+
+	rootCmd.AddCommand(shoppingListCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/export"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+	"github.com/spf13/cobra"
+)
+
+// Whether to re-render every page of the site
+var siteForce bool
+
+// Whether to parse temperatures in the site's recipes
+var siteTemperatures bool
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Builds a static website of the recipes folder",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var siteBuildCmd = &cobra.Command{
+	Use:   "build <outdir>",
+	Short: "Builds a static website of the recipes folder",
+	Long: `Builds a static website of every recipe in the recipes folder into <outdir>.
+
+The site holds a page per recipe, a listing per folder, a tag index (from the
+'tags' metadata of each recipe) and a search page backed by search.json. Every
+link is relative, so the output can be hosted anywhere as plain files.
+
+Rebuilding into the same directory only re-renders recipes which changed since
+the last build, use --force to re-render everything.
+
+Templates are shared with 'cook export html' (see [export] template-dir), adding
+folder.html, tags.html and search.html.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Help()
+			os.Exit(0)
+		}
+
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		outDir := args[0]
+		opts := export.SiteOptions{
+			HTMLOptions: export.HTMLOptions{
+				TemplateDir: config.GetConfig().Export.TemplateDir,
+				Render:      recipe.RenderOptions{Units: config.GetConfig().Units, Temperatures: siteTemperatures},
+			},
+			Force: siteForce,
+		}
+
+		stats, err := export.BuildSite(outDir, opts)
+		if err != nil {
+			errTxt := fmt.Sprintf("Site build failed: %v\n", err)
+			os.Stderr.WriteString(errTxt)
+			os.Exit(1)
+		}
+		fmt.Printf("Built %v: %v rendered, %v unchanged, %v removed\n",
+			outDir, stats.Rendered, stats.Skipped, stats.Removed)
+	},
+}
+
+func init() {
+	siteBuildCmd.Flags().BoolVar(&siteForce, "force", false,
+		"Re-render every recipe, even if unchanged")
+	siteBuildCmd.Flags().BoolVar(&siteTemperatures, "temperatures", false,
+		"Parses temperatures in step text, showing them in both scales (or the configured units)")
+
+	siteCmd.AddCommand(siteBuildCmd)
+	rootCmd.AddCommand(siteCmd)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/pantry"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+	"github.com/spf13/cobra"
+)
+
+// Filters of suggested recipes
+var (
+	suggestMissing int
+	suggestTags    []string
+	suggestTime    string
+)
+
+var suggestCmd = &cobra.Command{
+	Use:   "suggest",
+	Short: "Suggests recipes which can be cooked from the pantry",
+	Long: `Ranks recipes by how many of their ingredients are in the pantry (see cook pantry), fewest
+missing first, and lists what's missing from each.
+
+Sub-recipes are expanded into their ingredients. Ingredients followed by "(optional)" in a
+step, e.g. @chives{}(optional), aren't needed.`,
+
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		opts := api.SuggestOptions{MaxMissing: suggestMissing, Tags: suggestTags}
+		if suggestTime != "" {
+			if opts.MaxTime, err = units.ParseDuration(suggestTime); err != nil {
+				os.Stderr.WriteString(fmt.Sprintf("Invalid --time %q: %v\n", suggestTime, err))
+				os.Exit(1)
+			}
+		}
+
+		var suggestions []pantry.Suggestion
+		if suggestions, err = api.SuggestRecipes(opts); err != nil {
+			os.Stderr.WriteString(fmt.Sprintf("Failed to suggest recipes: %v\n", err))
+			os.Exit(1)
+		}
+		if len(suggestions) == 0 {
+			fmt.Println("No recipes match.")
+			return
+		}
+
+		wr := tabwriter.NewWriter(os.Stdout, 0, 4, 4, ' ', 0)
+		for _, s := range suggestions {
+			minutes := ""
+			if s.Minutes > 0 {
+				minutes = fmt.Sprintf("%v min", s.Minutes)
+			}
+			missing := "nothing missing"
+			if len(s.Missing) > 0 {
+				missing = "missing " + strings.Join(s.Missing, ", ")
+			}
+			fmt.Fprintf(wr, "%v\t%v/%v in stock\t%v\t%v\n", s.Recipe, s.InStock, s.Total, minutes, missing)
+		}
+		wr.Flush()
+	},
+}
+
+func init() {
+	suggestCmd.Flags().IntVarP(&suggestMissing, "missing", "m", -1,
+		"The most ingredients a recipe may be missing")
+	suggestCmd.Flags().StringSliceVarP(&suggestTags, "tag", "t", []string{},
+		"Only suggests recipes with these tags")
+	suggestCmd.Flags().StringVar(&suggestTime, "time", "",
+		"Only suggests recipes taking at most this long, e.g. \"30 min\"")
+
+	rootCmd.AddCommand(suggestCmd)
+}
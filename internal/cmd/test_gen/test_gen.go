@@ -32,8 +32,26 @@ type Test struct {
 }
 
 type Result struct {
-	Steps    [][]Chunk         `yaml:"steps"`
-	Metadata map[string]string `yaml:"metadata"`
+	Steps    [][]Chunk   `yaml:"steps"`
+	Metadata OrderedMeta `yaml:"metadata"`
+}
+
+// Metadata as defined in the spec, with the order of its keys preserved
+type OrderedMeta cook.Metadata
+
+// Decodes a yaml mapping into `OrderedMeta`, keeping the key order of the source
+func (m *OrderedMeta) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("metadata must be a mapping, line %v", node.Line)
+	}
+
+	meta := cook.Metadata{}
+	// Mapping nodes alternate between key and value nodes
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		meta.Add(node.Content[i].Value, node.Content[i+1].Value)
+	}
+	*m = OrderedMeta(meta)
+	return nil
 }
 
 type Chunk struct {
@@ -182,18 +200,26 @@ func generateTestFunc(testName string, testData *Test) string {
 // Converts `Test` to a `cook.Recipe`. `name` is used for error reporting.
 func testToRecipe(name string, test *Test) cook.Recipe {
 	r := cook.Recipe{
-		Name:        "",
-		Metadata:    map[string]string{},
-		Ingredients: []cook.Ingredient{},
-		Cookware:    []cook.Cookware{},
-		Timers:      []cook.Timer{},
-		Steps:       []cook.Step{},
+		Name:          "",
+		Metadata:      cook.Metadata{},
+		Ingredients:   []cook.Ingredient{},
+		Cookware:      []cook.Cookware{},
+		Timers:        []cook.Timer{},
+		Steps:         []cook.Step{},
+		StepManifests: []cook.StepManifest{},
 	}
 
-	r.Metadata = test.Result.Metadata
+	if test.Result.Metadata != nil {
+		r.Metadata = cook.Metadata(test.Result.Metadata)
+	}
 
 	for i, tStep := range test.Result.Steps {
 		r.Steps = append(r.Steps, cook.Step{})
+		manifest := cook.StepManifest{
+			Ingredients: []int{},
+			Cookware:    []int{},
+			Timers:      []int{},
+		}
 		for _, tChunk := range tStep {
 			switch tChunk.Type {
 			case "text":
@@ -205,6 +231,7 @@ func testToRecipe(name string, test *Test) cook.Recipe {
 					QtyVal: cook.TryParseQty(tChunk.Quantity),
 					Unit:   tChunk.Units,
 				}
+				manifest.Ingredients = append(manifest.Ingredients, len(r.Ingredients))
 				r.Ingredients = append(r.Ingredients, ingr)
 				r.Steps[i] = append(r.Steps[i], ingr)
 			case "cookware":
@@ -214,6 +241,7 @@ func testToRecipe(name string, test *Test) cook.Recipe {
 					QtyVal: cook.TryParseQty(tChunk.Quantity),
 					Unit:   tChunk.Units,
 				}
+				manifest.Cookware = append(manifest.Cookware, len(r.Cookware))
 				r.Cookware = append(r.Cookware, cookware)
 				r.Steps[i] = append(r.Steps[i], cookware)
 			case "timer":
@@ -223,6 +251,7 @@ func testToRecipe(name string, test *Test) cook.Recipe {
 					QtyVal: cook.TryParseQty(tChunk.Quantity),
 					Unit:   tChunk.Units,
 				}
+				manifest.Timers = append(manifest.Timers, len(r.Timers))
 				r.Timers = append(r.Timers, timer)
 				r.Steps[i] = append(r.Steps[i], timer)
 			default:
@@ -232,6 +261,7 @@ func testToRecipe(name string, test *Test) cook.Recipe {
 				os.Exit(1)
 			}
 		}
+		r.StepManifests = append(r.StepManifests, manifest)
 	}
 	return r
 }
@@ -239,11 +269,14 @@ func testToRecipe(name string, test *Test) cook.Recipe {
 // Creates a string of a Recipe being defined in Go code.
 func recipeToStrDef(r cook.Recipe) string {
 	var sb strings.Builder
-	sb.WriteString(`Recipe{Name:"",Metadata:map[string]string{`)
+	sb.WriteString(`Recipe{Name:"",Metadata:Metadata{`)
 	// Populate Metadata
-	for k, v := range r.Metadata {
-		str := fmt.Sprintf(`"%v":"%v",`, k, v)
-		sb.WriteString(str)
+	for _, entry := range r.Metadata {
+		sb.WriteString(fmt.Sprintf(`{Key:"%v",Values:[]string{`, entry.Key))
+		for _, v := range entry.Values {
+			sb.WriteString(fmt.Sprintf(`"%v",`, v))
+		}
+		sb.WriteString(`}},`)
 	}
 	sb.WriteString(`},Ingredients:[]Ingredient{`)
 	//Populate Ingredients
@@ -290,10 +323,29 @@ func recipeToStrDef(r cook.Recipe) string {
 		}
 		sb.WriteString("},")
 	}
+	sb.WriteString(`},StepManifests:[]StepManifest{`)
+	for _, manifest := range r.StepManifests {
+		sb.WriteString(fmt.Sprintf(
+			`{Ingredients:%s,Cookware:%s,Timers:%s},`,
+			intsToStrDef(manifest.Ingredients),
+			intsToStrDef(manifest.Cookware),
+			intsToStrDef(manifest.Timers)))
+	}
 	sb.WriteString("},}")
 	return sb.String()
 }
 
+// Builds the string definition of an int slice in Go code.
+func intsToStrDef(ints []int) string {
+	var sb strings.Builder
+	sb.WriteString(`[]int{`)
+	for _, i := range ints {
+		sb.WriteString(fmt.Sprintf(`%v,`, i))
+	}
+	sb.WriteString(`}`)
+	return sb.String()
+}
+
 // Builds the string definition of a component in Go code.
 func componentToStrDef(c cook.Component) string {
 	qtyVal := "NoQty"
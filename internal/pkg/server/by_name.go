@@ -7,11 +7,17 @@ import (
 	"strings"
 
 	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
 )
 
 // Handles requests to get/change individual recipes via the `name` URL parameter
 //   - GET :returns the parsed recipe as JSON
 //     [param `raw=<true/false>` will return the raw recipe file, unparsed.]
+//     [param `format=<format>` will return the recipe rendered in another
+//     format instead, e.g. `jsonld` for schema.org JSON-LD or `canonical` for
+//     the canonical JSON shape of the cooklang spec]
+//     [param `temperatures=<true/false>` will parse temperatures in the step
+//     text into typed chunks, off by default]
 //   - DELETE: deletes the recipe from the server
 //   - POST: update the file with the POST body as text (UNIMPL.)
 //     [param `rename=<string>` will move the recipe to the passed string.
@@ -35,7 +41,9 @@ func apiRecipeByName(name string, w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		// Try to grab raw param if it exists
 		raw := r.URL.Query().Get("raw")
-		handleRecipeByNameGET(name, raw, w)
+		format := r.URL.Query().Get("format")
+		temperatures := r.URL.Query().Get("temperatures")
+		handleRecipeByNameGET(name, raw, format, temperatures, w)
 	case http.MethodPost:
 		rename := r.URL.Query().Get("rename")
 		handleRecipeByNamePOST(name, rename, &body, w)
@@ -57,7 +65,7 @@ func apiRecipeByName(name string, w http.ResponseWriter, r *http.Request) {
 }
 
 // Helper function to hangleGET requests for endpoint `recipes/byName`
-func handleRecipeByNameGET(name string, raw string, w http.ResponseWriter) {
+func handleRecipeByNameGET(name string, raw string, format string, temperatures string, w http.ResponseWriter) {
 	var err error
 	var recipeData []byte
 
@@ -67,9 +75,41 @@ func handleRecipeByNameGET(name string, raw string, w http.ResponseWriter) {
 		return
 	}
 
+	// Validate `temperatures` param
+	if temperatures != "" && temperatures != "true" && temperatures != "false" {
+		http.Error(w, "Malformed Query, invalid `temperatures` parameter.", http.StatusUnprocessableEntity)
+		return
+	}
+
+	// Validate `format` param
+	if format == "canonical" {
+		format = "canonical-json"
+	}
+	if format != "" && format != "json" {
+		if raw == "true" {
+			http.Error(w, "Malformed Query, `raw` and `format` are exclusive.", http.StatusUnprocessableEntity)
+			return
+		}
+		if _, err = recipe.GetRenderer(format); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
 	// Fetch the relevant bytedata
-	if raw != "true" {
-		if recipeData, err = api.GetRecipeJSON(name); err != nil {
+	if format != "" && format != "json" {
+		if recipeData, err = api.GetRecipeFormatted(name, format, temperatures == "true"); err != nil {
+			http.Error(w, "Failed to load recipe file.", http.StatusInternalServerError)
+			return
+		}
+		switch format {
+		case "jsonld":
+			w.Header().Set("Content-Type", "application/ld+json")
+		case "canonical-json":
+			w.Header().Set("Content-Type", "application/json")
+		}
+	} else if raw != "true" {
+		if recipeData, err = api.GetRecipeJSON(name, temperatures == "true"); err != nil {
 			http.Error(w, "Failed to load recipe file.", http.StatusInternalServerError)
 			return
 		}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestRecipeDeps(t *testing.T) {
+	recipes := loadTestConfig(t)
+	sources := map[string]string{
+		"toast":  "Spread @./butter{} on @bread{2%slices}.\n",
+		"butter": "Churn @cream{500%ml}.\n",
+		"a":      "Mix @./b{}.\n",
+		"b":      "Mix @./a{}.\n",
+		"broken": "Mix @./nowhere{}.\n",
+	}
+	for name, source := range sources {
+		if err := os.WriteFile(filepath.Join(recipes, name+".cook"), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := map[string]int{
+		"toast":   http.StatusOK,
+		"missing": http.StatusNotFound,
+		"a":       http.StatusUnprocessableEntity,
+		"broken":  http.StatusUnprocessableEntity,
+	}
+	for name, want := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/api/0/recipes/deps?name="+name, nil)
+		rec := httptest.NewRecorder()
+		apiRecipeDeps(rec, req)
+		if rec.Code != want {
+			t.Fatalf("Wrong status for %v\ngot: %v %v\nwant: %v.", name, rec.Code, rec.Body.String(), want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"github.com/BurntSushi/toml"
+)
+
+// Loads a config with empty recipes and shopping directories (and a fixed
+// hmac-key), returning the recipes directory. Imports may fetch from local
+// test servers.
+func loadTestConfig(t *testing.T) string {
+	dir := t.TempDir()
+	conf := config.Config{
+		Recipe:   config.RecipeConfig{Dir: filepath.Join(dir, "recipes")},
+		Shopping: config.ShoppingConfig{Dir: filepath.Join(dir, "shopping")},
+		Import:   config.ImportConfig{AllowPrivate: true},
+		HMACKey:  "00112233445566778899aabbccddeeff",
+	}
+	if err := os.Mkdir(conf.Recipe.Dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(dir, "config.toml")
+	file, err := os.Create(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err = toml.NewEncoder(file).Encode(conf); err != nil {
+		t.Fatal(err)
+	}
+	if !config.LoadConfig(configPath) {
+		t.Fatal("Failed to load test config")
+	}
+	return conf.Recipe.Dir
+}
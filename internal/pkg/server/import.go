@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/importer"
+)
+
+// How long to wait for a page being imported
+var importTimeout = 15 * time.Second
+
+// The largest page which will be imported, in bytes
+var importMaxSize int64 = 5 << 20
+
+// The response of `recipes/import`
+type importResponse struct {
+	Name   string `json:"name"` // The saved name, or a suggested one
+	Source string `json:"source"`
+	Saved  bool   `json:"saved"`
+}
+
+// Handles requests to import a recipe from a web page, using its schema.org
+// JSON-LD.
+//
+// Only accepts POST requests. Pages on private addresses are refused, unless
+// `allow-private` is set in the `[import]` config.
+//
+// params:
+//   - url <url> -- the page to import
+//   - save <true/false> -- saves the recipe, rather than only returning it
+//   - name <string> -- the recipe to save as (required to save)
+//
+// Responds with the recipe's name and cooklang source as JSON.
+func apiRecipeImport(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+
+	pageURL := r.URL.Query().Get("url")
+	if pageURL == "" {
+		http.Error(w, "Malformed Query, missing `url` parameter.", http.StatusUnprocessableEntity)
+		return
+	}
+
+	save := r.URL.Query().Get("save")
+	if save != "" && save != "true" && save != "false" {
+		http.Error(w, "Malformed Query, invalid `save` parameter.", http.StatusUnprocessableEntity)
+		return
+	}
+	name := r.URL.Query().Get("name")
+	if save == "true" && name == "" {
+		http.Error(w, "Malformed Query, missing `name` parameter.", http.StatusUnprocessableEntity)
+		return
+	}
+
+	var page []byte
+	if page, err = importer.FetchPage(pageURL, importTimeout, importMaxSize,
+		config.GetConfig().Import.AllowPrivate); err != nil {
+		errMsg := fmt.Sprintf("Failed to fetch page: %s", err)
+		http.Error(w, errMsg, http.StatusBadGateway)
+		return
+	}
+
+	var recipe importer.Recipe
+	if recipe, err = importer.ParseJSONLD(page); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if recipe.Source == "" {
+		recipe.Source = pageURL
+	}
+
+	resp := importResponse{
+		Name:   importer.FileName(recipe.Name),
+		Source: importer.ToCooklang(recipe),
+	}
+
+	if save == "true" {
+		source := []byte(resp.Source)
+		if err = api.CreateRecipe(name, &source); err != nil {
+			errMsg := fmt.Sprintf("Failed to save: %s", err)
+			http.Error(w, errMsg, http.StatusInternalServerError)
+			return
+		}
+		resp.Name = name
+		resp.Saved = true
+	}
+
+	var jsonBytes []byte
+	if jsonBytes, err = json.Marshal(resp); err != nil {
+		http.Error(w, "Failed to encode recipe.", http.StatusInternalServerError)
+		return
+	}
+	w.Write(jsonBytes)
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A recipe page, as served by a remote site
+const testRecipePage = `<html><head><script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "Recipe", "name": "Toast",
+ "recipeYield": "2 servings",
+ "recipeIngredient": ["2 slices bread", "1 tbsp butter"],
+ "recipeInstructions": [
+   {"@type": "HowToStep", "text": "Toast the bread."},
+   {"@type": "HowToStep", "text": "Spread with butter."}]}
+</script></head><body></body></html>`
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestRecipeImport(t *testing.T) {
+	recipes := loadTestConfig(t)
+
+	// The stand-in for remote sites
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/toast":
+			w.Write([]byte(testRecipePage))
+		case "/empty":
+			w.Write([]byte("<html></html>"))
+		case "/large":
+			w.Write([]byte(strings.Repeat(" ", int(importMaxSize)+1)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer site.Close()
+
+	request := func(method string, params url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/0/recipes/import?"+params.Encode(), nil)
+		rec := httptest.NewRecorder()
+		apiRecipeImport(rec, req)
+		return rec
+	}
+	testStatus := func(method string, params url.Values, want int) {
+		if got := request(method, params).Code; got != want {
+			t.Fatalf("Wrong status for %v %v\ngot: %v\nwant: %v.", method, params, got, want)
+		}
+	}
+
+	// Proposed source, without saving
+	rec := request(http.MethodPost, url.Values{"url": {site.URL + "/toast"}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Import failed: %v %v", rec.Code, rec.Body.String())
+	}
+	var resp importResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Name != "toast" || resp.Saved {
+		t.Fatalf("Wrong response: %+v", resp)
+	}
+	if !strings.Contains(resp.Source, ">> source: "+site.URL+"/toast") ||
+		!strings.Contains(resp.Source, "Toast the @bread{2%slices}.") {
+		t.Fatalf("Wrong source:\n%v", resp.Source)
+	}
+	if entries, _ := os.ReadDir(recipes); len(entries) != 0 {
+		t.Fatalf("Recipe was saved without `save=true`")
+	}
+
+	// Saving
+	testStatus(http.MethodPost, url.Values{"url": {site.URL + "/toast"}, "save": {"true"}},
+		http.StatusUnprocessableEntity)
+	testStatus(http.MethodPost, url.Values{
+		"url": {site.URL + "/toast"}, "save": {"true"}, "name": {"breakfast_toast"}},
+		http.StatusOK)
+	saved, err := os.ReadFile(filepath.Join(recipes, "breakfast_toast.cook"))
+	if err != nil || string(saved) != resp.Source {
+		t.Fatalf("Recipe was not saved: %v\n%v", err, string(saved))
+	}
+	testStatus(http.MethodPost, url.Values{
+		"url": {site.URL + "/toast"}, "save": {"true"}, "name": {"breakfast/toast"}},
+		http.StatusOK)
+	if _, err = os.Stat(filepath.Join(recipes, "breakfast", "toast.cook")); err != nil {
+		t.Fatalf("Recipe was not saved into a new folder: %v", err)
+	}
+
+	// Failures
+	testStatus(http.MethodGet, url.Values{"url": {site.URL + "/toast"}}, http.StatusNotFound)
+	testStatus(http.MethodPost, url.Values{}, http.StatusUnprocessableEntity)
+	testStatus(http.MethodPost, url.Values{"url": {"file:///etc/passwd"}}, http.StatusBadGateway)
+	testStatus(http.MethodPost, url.Values{"url": {site.URL + "/missing"}}, http.StatusBadGateway)
+	testStatus(http.MethodPost, url.Values{"url": {site.URL + "/large"}}, http.StatusBadGateway)
+	testStatus(http.MethodPost, url.Values{"url": {site.URL + "/empty"}},
+		http.StatusUnprocessableEntity)
+}
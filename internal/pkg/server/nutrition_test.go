@@ -0,0 +1,83 @@
+package server
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/nutrition"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestRecipeNutrition(t *testing.T) {
+	recipes := loadTestConfig(t)
+	sources := map[string]string{
+		"toast":  ">> servings: 2\nToast @bread{100%g} and spread @./spread{}, with @salt.\n",
+		"spread": "Mix @butter{20%g} with @honey{1%tbsp}.\n",
+	}
+	for name, source := range sources {
+		if err := os.WriteFile(filepath.Join(recipes, name+".cook"), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type recipeResponse struct {
+		Name      string           `json:"name"`
+		Nutrition *nutrition.Facts `json:"nutrition"`
+	}
+	request := func() recipeResponse {
+		req := httptest.NewRequest(http.MethodGet, "/api/0/recipes/?name=toast", nil)
+		rec := httptest.NewRecorder()
+		apiRecipe(rec, req)
+
+		var resp recipeResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Invalid recipe: %v\n%v", err, rec.Body.String())
+		}
+		return resp
+	}
+
+	// Left out without a nutrient table
+	if resp := request(); resp.Name != "toast" || resp.Nutrition != nil {
+		t.Fatalf("Wrong recipe without a nutrient table: %+v", resp)
+	}
+
+	dir := filepath.Dir(recipes)
+	files := map[string]string{
+		"nutrition.csv":          "name,calories,protein,fat,carbs,fibre\nBread,250,9,3,49,3\nButter,717,1,81,0,0\n",
+		"nutrition-aliases.conf": "Bread|toast bread\n",
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	facts := request().Nutrition
+	if facts == nil {
+		t.Fatal("Recipe is missing its nutrition")
+	}
+	// 100 g of bread and 20 g of butter
+	want := nutrition.Nutrients{Calories: 250 + 143.4, Protein: 9 + 0.2, Fat: 3 + 16.2, Carbs: 49, Fibre: 3}
+	near := func(a float64, b float64) bool {
+		return math.Abs(a-b) < 0.01
+	}
+	got := facts.Total
+	if !near(got.Calories, want.Calories) || !near(got.Protein, want.Protein) || !near(got.Fat, want.Fat) ||
+		!near(got.Carbs, want.Carbs) || !near(got.Fibre, want.Fibre) {
+		t.Fatalf("Wrong total\ngot: %+v\nwant: %+v", got, want)
+	}
+	if facts.PerServing == nil || facts.Servings != 2 {
+		t.Fatalf("Wrong servings: %+v", facts)
+	}
+	if len(facts.Unmatched) != 2 || facts.Unmatched[0].Name != "honey" || facts.Unmatched[1].Name != "salt" {
+		t.Fatalf("Wrong unmatched: %+v", facts.Unmatched)
+	}
+}
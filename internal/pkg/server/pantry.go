@@ -0,0 +1,137 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/pantry"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+// Handles requests for the pantry.
+//
+// GET responds with the pantry as JSON.
+//
+// POST adds an item, params:
+//   - item <string> -- the item, e.g. "2 kg flour"
+//   - expires <YYYY-MM-DD> -- the date the item expires (optional)
+//
+// DELETE removes an item, params:
+//   - item <string> -- the item, without a quantity to remove all of it
+//
+// POST and DELETE respond with the updated pantry as JSON.
+func apiPantry(w http.ResponseWriter, r *http.Request) {
+	var err error
+	var p pantry.Pantry
+
+	switch r.Method {
+	case http.MethodGet:
+		p, err = api.GetPantry()
+	case http.MethodPost:
+		if r.URL.Query().Get("item") == "" {
+			http.Error(w, "Malformed Query, missing `item` parameter.", http.StatusUnprocessableEntity)
+			return
+		}
+		if p, err = api.AddPantryItem(r.URL.Query().Get("item"), r.URL.Query().Get("expires")); err != nil {
+			errMsg := fmt.Sprintf("Malformed Query, %s", err)
+			http.Error(w, errMsg, http.StatusUnprocessableEntity)
+			return
+		}
+	case http.MethodDelete:
+		if r.URL.Query().Get("item") == "" {
+			http.Error(w, "Malformed Query, missing `item` parameter.", http.StatusUnprocessableEntity)
+			return
+		}
+		if p, err = api.RemovePantryItem(r.URL.Query().Get("item")); err != nil {
+			if errors.Is(err, pantry.ErrItemNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+			} else {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			}
+			return
+		}
+	default:
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, "Failed to read pantry.", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+// Handles requests for pantry items which expire soon, soonest first.
+//
+// Only accepts GET requests.
+//
+// params:
+//   - days <uint> -- how many days ahead to look (default 7)
+func apiPantryExpiring(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+
+	days := 7
+	if param := r.URL.Query().Get("days"); param != "" {
+		if days, err = strconv.Atoi(param); err != nil || days < 0 {
+			http.Error(w, "Malformed Query, invalid `days` parameter.", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	var p pantry.Pantry
+	if p, err = api.GetPantry(); err != nil {
+		http.Error(w, "Failed to read pantry.", http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, p.Expiring(time.Now(), days))
+}
+
+// Handles requests for recipes which can be cooked from the pantry, ranked by
+// how many of their ingredients are in stock.
+//
+// Only accepts GET requests.
+//
+// params:
+//   - missing <uint> -- the most ingredients a recipe may be missing (optional)
+//   - tag <string> -- a tag recipes must have, may be repeated (optional)
+//   - time <duration> -- the longest total time, e.g. "30 min" (optional)
+func apiRecipeSuggest(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+
+	opts := api.SuggestOptions{MaxMissing: -1, Tags: r.URL.Query()["tag"]}
+	if param := r.URL.Query().Get("missing"); param != "" {
+		if opts.MaxMissing, err = strconv.Atoi(param); err != nil || opts.MaxMissing < 0 {
+			http.Error(w, "Malformed Query, invalid `missing` parameter.", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+	if param := r.URL.Query().Get("time"); param != "" {
+		if opts.MaxTime, err = units.ParseDuration(param); err != nil {
+			http.Error(w, "Malformed Query, invalid `time` parameter.", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+
+	var suggestions []pantry.Suggestion
+	if suggestions, err = api.SuggestRecipes(opts); err != nil {
+		errMsg := fmt.Sprintf("Failed to suggest recipes: %s", err)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, suggestions)
+}
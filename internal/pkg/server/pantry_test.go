@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/pantry"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestPantry(t *testing.T) {
+	recipes := loadTestConfig(t)
+	pancakes := "Mix @flour{2%cups}, @milk{1%l} and @eggs{2}.\n"
+	if err := os.WriteFile(filepath.Join(recipes, "pancakes.cook"), []byte(pancakes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(handler http.HandlerFunc, method string, params url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/0/pantry?"+params.Encode(), nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	testStatus := func(handler http.HandlerFunc, method string, params url.Values, want int) {
+		if got := request(handler, method, params).Code; got != want {
+			t.Fatalf("Wrong status for %v %v\ngot: %v\nwant: %v.", method, params, got, want)
+		}
+	}
+
+	testStatus(apiPantry, http.MethodPost, url.Values{"item": {"3 cups flour"}}, http.StatusOK)
+	testStatus(apiPantry, http.MethodPost,
+		url.Values{"item": {"250 ml milk"}, "expires": {"2000-01-01"}}, http.StatusOK)
+	testStatus(apiPantry, http.MethodPost,
+		url.Values{"item": {"eggs"}, "expires": {"soon"}}, http.StatusUnprocessableEntity)
+	testStatus(apiPantry, http.MethodDelete, url.Values{"item": {"butter"}}, http.StatusNotFound)
+	testStatus(apiPantry, http.MethodPut, url.Values{}, http.StatusNotFound)
+
+	var p pantry.Pantry
+	if err := json.Unmarshal(request(apiPantry, http.MethodGet, url.Values{}).Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Items) != 2 || p.Items[0].AmountText() != "3 cups" {
+		t.Fatalf("Wrong pantry: %+v", p)
+	}
+
+	var expiring []pantry.Item
+	rec := request(apiPantryExpiring, http.MethodGet, url.Values{"days": {"3"}})
+	if err := json.Unmarshal(rec.Body.Bytes(), &expiring); err != nil {
+		t.Fatal(err)
+	}
+	if len(expiring) != 1 || expiring[0].Name != "milk" {
+		t.Fatalf("Wrong expiring items: %+v", expiring)
+	}
+
+	// Stock is left off shopping lists
+	rec = request(apiShoppingLists, http.MethodPost, url.Values{"name": {"week"}, "recipe": {"pancakes"}})
+	var list shopping.List
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, item := range list.Items {
+		got[item.Name] = item.AmountText()
+	}
+	if len(got) != 2 || got["milk"] != "0.75 l" || got["eggs"] != "2" {
+		t.Fatalf("Pantry wasn't subtracted from the list: %v", got)
+	}
+}
+
+func TestRecipeSuggest(t *testing.T) {
+	recipes := loadTestConfig(t)
+	sources := map[string]string{
+		"omelette": ">> tags: quick\n>> time: 10 min\nWhisk @eggs{3}, add @chives{}(optional).\n",
+		"pancakes": ">> time: 30 min\nMix @flour{2%cups}, @milk{1%cup} and @eggs{2}.\n",
+	}
+	for name, source := range sources {
+		if err := os.WriteFile(filepath.Join(recipes, name+".cook"), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := api.AddPantryItem("6 eggs", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	testSuggest := func(params url.Values, want string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/0/recipes/suggest?"+params.Encode(), nil)
+		rec := httptest.NewRecorder()
+		apiRecipeSuggest(rec, req)
+
+		var suggestions []pantry.Suggestion
+		if err := json.Unmarshal(rec.Body.Bytes(), &suggestions); err != nil {
+			t.Fatalf("Invalid response to %v: %v\n%v", params, err, rec.Body.String())
+		}
+		got := make([]string, 0, len(suggestions))
+		for _, s := range suggestions {
+			got = append(got, fmt.Sprintf("%v %v", s.Recipe, s.Missing))
+		}
+		if strings.Join(got, ", ") != want {
+			t.Fatalf("Wrong suggestions for %v\ngot: %v\nwant: %v.", params, strings.Join(got, ", "), want)
+		}
+	}
+
+	testSuggest(url.Values{}, "omelette [], pancakes [flour milk]")
+	testSuggest(url.Values{"missing": {"1"}}, "omelette []")
+	testSuggest(url.Values{"tag": {"Quick"}}, "omelette []")
+	testSuggest(url.Values{"time": {"20 minutes"}}, "omelette []")
+	testSuggest(url.Values{"time": {"1 hour"}, "tag": {"dessert"}}, "")
+}
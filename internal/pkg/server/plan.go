@@ -0,0 +1,211 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/plan"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// The response of `plan` requests for a week
+type planWeekResponse struct {
+	Week  string      `json:"week"`
+	Start string      `json:"start"` // The Monday the week starts on
+	Meals []plan.Meal `json:"meals"`
+}
+
+// Handles requests for the meal plan.
+//
+// GET responds with the plan as JSON, params:
+//   - week <YYYY-Www> -- only the meals of this week, e.g. 2026-W43 (optional)
+//
+// POST plans a recipe for a meal, params:
+//   - date <YYYY-MM-DD> -- the day to plan for
+//   - recipe <string> -- the recipe's name
+//   - meal <string> -- the meal to plan for (default dinner)
+//   - servings <float> -- the servings to cook (default as written)
+//
+// DELETE removes planned meals, params:
+//   - date <YYYY-MM-DD> -- the day to remove meals from
+//   - meal <string> -- only removes recipes of this meal (optional)
+//   - recipe <string> -- only removes this recipe (optional)
+//
+// POST and DELETE respond with the updated plan as JSON.
+func apiPlan(w http.ResponseWriter, r *http.Request) {
+	var err error
+	var p plan.Plan
+	query := r.URL.Query()
+
+	switch r.Method {
+	case http.MethodGet:
+		if week := query.Get("week"); week != "" {
+			if _, err = plan.ParseWeek(week); err != nil {
+				http.Error(w, "Malformed Query, invalid `week` parameter.", http.StatusUnprocessableEntity)
+				return
+			}
+			var meals []plan.Meal
+			var monday time.Time
+			if meals, monday, err = api.GetWeekMeals(week); err != nil {
+				http.Error(w, "Failed to read plan.", http.StatusInternalServerError)
+				return
+			}
+			resp := planWeekResponse{Week: week, Start: monday.Format(plan.DateFormat), Meals: meals}
+			writeJSON(w, http.StatusOK, resp)
+			return
+		}
+		if p, err = api.GetPlan(); err != nil {
+			http.Error(w, "Failed to read plan.", http.StatusInternalServerError)
+			return
+		}
+
+	case http.MethodPost:
+		date, ok := planDate(w, r)
+		if !ok {
+			return
+		}
+		if query.Get("recipe") == "" {
+			http.Error(w, "Malformed Query, missing `recipe` parameter.", http.StatusUnprocessableEntity)
+			return
+		}
+		meal := plan.Meal{Date: date, Meal: query.Get("meal"), Recipe: query.Get("recipe")}
+		if servings := query.Get("servings"); servings != "" {
+			if meal.Servings, err = strconv.ParseFloat(servings, 64); err != nil || meal.Servings <= 0 {
+				http.Error(w, "Malformed Query, invalid `servings` parameter.", http.StatusUnprocessableEntity)
+				return
+			}
+		}
+
+		if p, err = api.AddMeal(meal); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+	case http.MethodDelete:
+		date, ok := planDate(w, r)
+		if !ok {
+			return
+		}
+		if p, err = api.RemoveMeal(date, query.Get("meal"), query.Get("recipe")); err != nil {
+			if errors.Is(err, plan.ErrMealNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+			} else {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+	default:
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, p)
+}
+
+// Handles requests for the shopping list of a week of meals.
+//
+// params:
+//   - week <YYYY-Www> -- the week to shop for, e.g. 2026-W43
+//
+// GET responds with the list as JSON, without saving it.
+//
+// POST saves the list into the shopping folder (see `shopping/list`), params:
+//   - name <string> -- the name to save the list as
+func apiPlanShoppingList(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	week := r.URL.Query().Get("week")
+	if _, err = plan.ParseWeek(week); err != nil {
+		http.Error(w, "Malformed Query, invalid `week` parameter.", http.StatusUnprocessableEntity)
+		return
+	}
+
+	var list shopping.List
+	switch r.Method {
+	case http.MethodGet:
+		if list, err = api.BuildPlanShoppingList("", week); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+
+	case http.MethodPost:
+		name, ok := shoppingListName(w, r)
+		if !ok {
+			return
+		}
+		var args []string
+		if args, err = api.PlanShoppingArgs(week); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		if list, err = api.CreateShoppingList(name, args); err != nil {
+			if errors.Is(err, api.ErrListExists) {
+				http.Error(w, err.Error(), http.StatusConflict)
+			} else {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			}
+			return
+		}
+		writeJSON(w, http.StatusCreated, list)
+
+	default:
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+	}
+}
+
+// Reads the `date` parameter of a plan request (see `plan.ParseDate`),
+// responding with an error if it is missing or invalid.
+func planDate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	param := r.URL.Query().Get("date")
+	if param == "" {
+		http.Error(w, "Malformed Query, missing `date` parameter.", http.StatusUnprocessableEntity)
+		return "", false
+	}
+	date, err := plan.ParseDate(param, time.Now())
+	if err != nil {
+		http.Error(w, "Malformed Query, invalid `date` parameter.", http.StatusUnprocessableEntity)
+		return "", false
+	}
+	return date, true
+}
+
+// Handles requests for the meal plan as an iCalendar feed, for calendar apps
+// to subscribe to.
+//
+// Only accepts GET requests.
+//
+// params:
+//   - token <string> -- grants access to the feed (see `cook plan feed-url`)
+func apiPlanICS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+	if !api.ValidatePlanFeedToken(r.URL.Query().Get("token")) {
+		http.Error(w, "Invalid feed token.", http.StatusForbidden)
+		return
+	}
+
+	// Events link back to this server, unless the config says otherwise
+	baseURL := config.GetConfig().Calendar.URL
+	if baseURL == "" {
+		baseURL = "http://" + r.Host
+	}
+
+	var buf bytes.Buffer
+	if err := api.WritePlanICS(&buf, baseURL); err != nil {
+		errMsg := fmt.Sprintf("Failed to build calendar: %s", err)
+		http.Error(w, errMsg, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(buf.Bytes())
+}
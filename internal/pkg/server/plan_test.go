@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/plan"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestPlan(t *testing.T) {
+	recipes := loadTestConfig(t)
+	sources := map[string]string{
+		"pasta": ">> servings: 2\nBoil @pasta{200%g} with @salt{}.\n",
+		"soup":  "Simmer @tomatoes{500%g} with @salt{}.\n",
+	}
+	for name, source := range sources {
+		if err := os.WriteFile(filepath.Join(recipes, name+".cook"), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := func(handler http.HandlerFunc, method string, params url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/0/plan?"+params.Encode(), nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	testStatus := func(handler http.HandlerFunc, method string, params url.Values, want int) {
+		if got := request(handler, method, params).Code; got != want {
+			t.Fatalf("Wrong status for %v %v\ngot: %v\nwant: %v.", method, params, got, want)
+		}
+	}
+
+	testStatus(apiPlan, http.MethodPost,
+		url.Values{"date": {"2026-10-20"}, "recipe": {"pasta"}, "servings": {"4"}}, http.StatusOK)
+	testStatus(apiPlan, http.MethodPost,
+		url.Values{"date": {"2026-10-21"}, "recipe": {"soup"}, "meal": {"lunch"}}, http.StatusOK)
+	testStatus(apiPlan, http.MethodPost,
+		url.Values{"date": {"2026-10-27"}, "recipe": {"soup"}}, http.StatusOK)
+	testStatus(apiPlan, http.MethodPost,
+		url.Values{"date": {"2026-10-20"}, "recipe": {"missing"}}, http.StatusUnprocessableEntity)
+	testStatus(apiPlan, http.MethodPost,
+		url.Values{"date": {"soon"}, "recipe": {"soup"}}, http.StatusUnprocessableEntity)
+	testStatus(apiPlan, http.MethodPost,
+		url.Values{"date": {"2026-10-20"}, "recipe": {"soup"}, "servings": {"-1"}},
+		http.StatusUnprocessableEntity)
+
+	var week planWeekResponse
+	rec := request(apiPlan, http.MethodGet, url.Values{"week": {"2026-W43"}})
+	if err := json.Unmarshal(rec.Body.Bytes(), &week); err != nil {
+		t.Fatal(err)
+	}
+	if week.Start != "2026-10-19" || len(week.Meals) != 2 ||
+		week.Meals[0] != (plan.Meal{Date: "2026-10-20", Meal: "dinner", Recipe: "pasta", Servings: 4}) {
+		t.Fatalf("Wrong week: %+v", week)
+	}
+	testStatus(apiPlan, http.MethodGet, url.Values{"week": {"W43"}}, http.StatusUnprocessableEntity)
+
+	// Shopping for the week
+	var list shopping.List
+	rec = request(apiPlanShoppingList, http.MethodGet, url.Values{"week": {"2026-W43"}})
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("Invalid list: %v\n%v", err, rec.Body.String())
+	}
+	got := map[string]string{}
+	for _, item := range list.Items {
+		got[item.Name] = item.AmountText()
+	}
+	if len(got) != 3 || got["pasta"] != "400 g" || got["tomatoes"] != "500 g" {
+		t.Fatalf("Wrong shopping list: %v", got)
+	}
+	testStatus(apiPlanShoppingList, http.MethodGet, url.Values{"week": {"2026-W45"}},
+		http.StatusUnprocessableEntity)
+	testStatus(apiPlanShoppingList, http.MethodPost,
+		url.Values{"week": {"2026-W43"}, "name": {"week 43"}}, http.StatusCreated)
+	testStatus(apiPlanShoppingList, http.MethodPost,
+		url.Values{"week": {"2026-W43"}, "name": {"week 43"}}, http.StatusConflict)
+
+	// Removing
+	testStatus(apiPlan, http.MethodDelete,
+		url.Values{"date": {"2026-10-21"}, "meal": {"dinner"}}, http.StatusNotFound)
+	testStatus(apiPlan, http.MethodDelete, url.Values{"date": {"2026-10-21"}}, http.StatusOK)
+
+	var p plan.Plan
+	if err := json.Unmarshal(request(apiPlan, http.MethodGet, url.Values{}).Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Meals) != 2 {
+		t.Fatalf("Wrong plan after removing: %+v", p)
+	}
+}
+
+func TestPlanICS(t *testing.T) {
+	recipes := loadTestConfig(t)
+	source := ">> servings: 2\nBoil @pasta{200%g}.\n"
+	if err := os.WriteFile(filepath.Join(recipes, "pasta.cook"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.AddMeal(plan.Meal{Date: "2026-10-20", Recipe: "pasta", Servings: 4}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := api.PlanFeedToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	request := func(method string, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "http://cook.local/api/0/plan.ics?token="+token, nil)
+		rec := httptest.NewRecorder()
+		apiPlanICS(rec, req)
+		return rec
+	}
+
+	rec := request(http.MethodGet, token)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/calendar; charset=utf-8" {
+		t.Fatalf("Feed failed: %v %v", rec.Code, rec.Body.String())
+	}
+	ics := strings.ReplaceAll(rec.Body.String(), "\r\n ", "")
+	for _, want := range []string{"SUMMARY:Dinner: pasta", "400 g pasta", "URL:http://cook.local/?recipe=pasta"} {
+		if !strings.Contains(ics, want) {
+			t.Fatalf("Feed is missing %q\ngot:\n%v", want, ics)
+		}
+	}
+
+	if got := request(http.MethodGet, token[:len(token)-1]+"x").Code; got != http.StatusForbidden {
+		t.Fatalf("Wrong status for a forged token\ngot: %v\nwant: %v.", got, http.StatusForbidden)
+	}
+	if got := request(http.MethodGet, "").Code; got != http.StatusForbidden {
+		t.Fatalf("Wrong status without a token\ngot: %v\nwant: %v.", got, http.StatusForbidden)
+	}
+	if got := request(http.MethodPost, token).Code; got != http.StatusNotFound {
+		t.Fatalf("Wrong status for POST\ngot: %v\nwant: %v.", got, http.StatusNotFound)
+	}
+}
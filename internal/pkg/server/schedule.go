@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/schedule"
+)
+
+// Handles requests for a cooking schedule, planning when to start each step
+// of the recipes so they are ready together (see `api.ScheduleRecipes`).
+//
+// Only accepts GET requests, responding with the schedule as JSON.
+//
+// params:
+//   - ready <time> -- when everything should be ready, e.g. 19:00 or "2026-10-20 19:00"
+//   - recipe <string> -- a recipe to cook, may be repeated
+func apiSchedule(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	var ready time.Time
+	if ready, err = schedule.ParseReady(query.Get("ready"), time.Now()); err != nil {
+		http.Error(w, "Malformed Query, invalid `ready` parameter.", http.StatusUnprocessableEntity)
+		return
+	}
+	if len(query["recipe"]) == 0 {
+		http.Error(w, "Malformed Query, missing `recipe` parameter.", http.StatusUnprocessableEntity)
+		return
+	}
+
+	var s schedule.Schedule
+	if s, err = api.ScheduleRecipes(query["recipe"], ready); err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	writeJSON(w, http.StatusOK, s)
+}
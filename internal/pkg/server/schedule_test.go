@@ -0,0 +1,84 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/schedule"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestSchedule(t *testing.T) {
+	recipes := loadTestConfig(t)
+	if err := os.MkdirAll(filepath.Join(recipes, "sauces"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]string{
+		"sauces/ragu": ">> active time: 15 min\nSimmer @mince{500%g} for ~{1%hour}.\n",
+		"lasagna":     "Layer @pasta{12} with @./sauces/ragu{}.\n\nBake in the #oven{} for ~{40%minutes}.\n",
+		"tart":        "Bake the @pastry{} in the #oven{} for ~{30%minutes}.\n",
+	}
+	for name, source := range sources {
+		if err := os.WriteFile(filepath.Join(recipes, name+".cook"), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	request := func(method string, params url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/0/schedule?"+params.Encode(), nil)
+		rec := httptest.NewRecorder()
+		apiSchedule(rec, req)
+		return rec
+	}
+
+	rec := request(http.MethodGet, url.Values{"ready": {"2026-10-20 19:00"}, "recipe": {"lasagna", "tart"}})
+	var s schedule.Schedule
+	if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+		t.Fatalf("Invalid schedule: %v\n%v", err, rec.Body.String())
+	}
+
+	got := map[string]schedule.Entry{}
+	for _, entry := range s.Entries {
+		got[entry.Recipe+" "+entry.Text] = entry
+	}
+	local := func(hour int, minute int) time.Time {
+		return time.Date(2026, 10, 20, hour, minute, 0, 0, time.Local)
+	}
+	want := map[string]time.Time{
+		"lasagna Bake in the oven for 40 minutes.":         local(18, 15),
+		"lasagna Layer pasta with ragu.":                   local(18, 10),
+		"sauces/ragu Simmer mince for 1 hour.":             local(16, 55),
+		"tart Bake the pastry in the oven for 30 minutes.": local(17, 40),
+	}
+	if len(got) != len(want) || !s.Ready.Equal(local(19, 0)) {
+		t.Fatalf("Wrong schedule: %+v", s)
+	}
+	for key, start := range want {
+		if entry, ok := got[key]; !ok || !entry.Start.Equal(start) {
+			t.Fatalf("Wrong start of %q\ngot: %+v\nwant: %v", key, entry, start)
+		}
+	}
+
+	for _, params := range []url.Values{
+		{"recipe": {"lasagna"}},
+		{"ready": {"7pm"}, "recipe": {"lasagna"}},
+		{"ready": {"19:00"}},
+		{"ready": {"19:00"}, "recipe": {"missing"}},
+	} {
+		if got := request(http.MethodGet, params).Code; got != http.StatusUnprocessableEntity {
+			t.Fatalf("Wrong status for %v\ngot: %v\nwant: %v.", params, got, http.StatusUnprocessableEntity)
+		}
+	}
+	if got := request(http.MethodPost, url.Values{}).Code; got != http.StatusNotFound {
+		t.Fatalf("Wrong status for POST\ngot: %v\nwant: %v.", got, http.StatusNotFound)
+	}
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"io/ioutil"
@@ -18,9 +19,25 @@ import (
 
 // The manifest of each API endpoint mapped to its handler
 var apiHandlerFuncs = map[string]func(http.ResponseWriter, *http.Request){
-	"recipes/parse": apiRecipeParse,
-	"recipes/names": apiRecipeNames,
-	"recipes/":      apiRecipe,
+	"recipes/parse":   apiRecipeParse,
+	"recipes/names":   apiRecipeNames,
+	"recipes/deps":    apiRecipeDeps,
+	"recipes/import":  apiRecipeImport,
+	"recipes/suggest": apiRecipeSuggest,
+	"recipes/":        apiRecipe,
+
+	"shopping/lists":      apiShoppingLists,
+	"shopping/list":       apiShoppingList,
+	"shopping/list/items": apiShoppingListItems,
+
+	"pantry":          apiPantry,
+	"pantry/expiring": apiPantryExpiring,
+
+	"plan":               apiPlan,
+	"plan/shopping-list": apiPlanShoppingList,
+	"plan.ics":           apiPlanICS,
+
+	"schedule": apiSchedule,
 }
 
 func Start(port int, onlyApi bool) {
@@ -53,6 +70,9 @@ func Start(port int, onlyApi bool) {
 }
 
 // Handles parse requests as POST bodies.
+//
+// [param `temperatures=true` will parse temperatures in the step text into
+// typed chunks, off by default]
 func apiRecipeParse(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method is not supported.", http.StatusNotFound)
@@ -69,6 +89,9 @@ func apiRecipeParse(w http.ResponseWriter, r *http.Request) {
 
 	// Parse body and encode to JSON
 	recipe := cook.ParseRecipe("", &body)
+	if r.URL.Query().Get("temperatures") == "true" {
+		cook.ParseTemperatures(&recipe)
+	}
 	jsonBytes, jsonErr := json.Marshal(&recipe)
 	if jsonErr != nil {
 		http.Error(w, "Error parsing recipe text", http.StatusBadRequest)
@@ -154,3 +177,40 @@ func apiRecipeNames(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(names)
 }
+
+// Handles requests for the dependencies of a recipe via the `name` URL parameter.
+//
+// Only accepts GET requests, returns the recipes it uses, is used by and its
+// ingredients (with each sub-recipe expanded) as JSON.
+//
+// Responds 404 for missing recipes, and 422 for broken references or cycles.
+func apiRecipeDeps(w http.ResponseWriter, r *http.Request) {
+	var err error
+	var deps []byte
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Malformed Query, missing `name` parameter.", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if deps, err = api.GetRecipeDepsJSON(name); err != nil {
+		errMsg := fmt.Sprintf("Failed to fetch dependencies: %s", err)
+		switch {
+		case errors.Is(err, api.ErrRecipeCycle), errors.Is(err, api.ErrBrokenRecipeRef):
+			http.Error(w, errMsg, http.StatusUnprocessableEntity)
+		case errors.Is(err, api.ErrRecipeNotFound):
+			http.Error(w, errMsg, http.StatusNotFound)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Write(deps)
+}
@@ -0,0 +1,236 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// Handles requests for saved shopping lists.
+//
+// GET lists the names of every saved list.
+//
+// POST builds a list from recipes and saves it, params:
+//   - name <string> -- the name to save the list as
+//   - recipe <recipe>[:servings] -- a recipe to shop for, may be repeated
+//
+// Responds with the saved list as JSON. Responds 404 for missing recipes, and
+// 422 for invalid servings, broken recipe references or cycles.
+func apiShoppingLists(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	switch r.Method {
+	case http.MethodGet:
+		var names []string
+		if names, err = api.GetShoppingListNames(); err != nil {
+			http.Error(w, "Failed to read shopping lists.", http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, names)
+
+	case http.MethodPost:
+		name, ok := shoppingListName(w, r)
+		if !ok {
+			return
+		}
+		recipes := r.URL.Query()["recipe"]
+		if len(recipes) == 0 {
+			http.Error(w, "Malformed Query, missing `recipe` parameter.", http.StatusUnprocessableEntity)
+			return
+		}
+
+		var list shopping.List
+		if list, err = api.CreateShoppingList(name, recipes); err != nil {
+			switch {
+			case errors.Is(err, api.ErrListExists):
+				http.Error(w, err.Error(), http.StatusConflict)
+			case errors.Is(err, shopping.ErrInvalidServings), errors.Is(err, shopping.ErrInvalidListName),
+				errors.Is(err, api.ErrRecipeCycle), errors.Is(err, api.ErrBrokenRecipeRef):
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			case errors.Is(err, api.ErrRecipeNotFound):
+				http.Error(w, err.Error(), http.StatusNotFound)
+			default:
+				errMsg := fmt.Sprintf("Failed to create shopping list: %s", err)
+				http.Error(w, errMsg, http.StatusInternalServerError)
+			}
+			return
+		}
+		writeJSON(w, http.StatusCreated, list)
+
+	default:
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+	}
+}
+
+// Handles requests for a single saved shopping list.
+//
+// params:
+//   - name <string> -- the list
+//
+// GET responds with the list as JSON.
+//
+// PATCH checks (or unchecks) an item, responding with the updated list, params:
+//   - id <int> -- the item
+//   - checked <true/false>
+//
+// DELETE removes the list.
+func apiShoppingList(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	name, ok := shoppingListName(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var list shopping.List
+		if list, err = api.GetShoppingList(name); err != nil {
+			writeShoppingError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+
+	case http.MethodPatch:
+		id, ok := shoppingItemID(w, r)
+		if !ok {
+			return
+		}
+		var checked bool
+		if checked, err = strconv.ParseBool(r.URL.Query().Get("checked")); err != nil {
+			http.Error(w, "Malformed Query, invalid `checked` parameter.", http.StatusUnprocessableEntity)
+			return
+		}
+
+		var list shopping.List
+		list, err = api.UpdateShoppingList(name, func(list *shopping.List) error {
+			return list.SetChecked(id, checked)
+		})
+		if err != nil {
+			writeShoppingError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+
+	case http.MethodDelete:
+		if err = api.DeleteShoppingList(name); err != nil {
+			writeShoppingError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+	}
+}
+
+// Handles requests to change the items of a saved shopping list.
+//
+// params:
+//   - name <string> -- the list
+//
+// POST adds a free-form item, params:
+//   - item <string> -- the item, e.g. "2 litres milk"
+//
+// DELETE removes an item, params:
+//   - id <int> -- the item
+//
+// Responds with the updated list as JSON.
+func apiShoppingListItems(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	name, ok := shoppingListName(w, r)
+	if !ok {
+		return
+	}
+
+	var update func(list *shopping.List) error
+	switch r.Method {
+	case http.MethodPost:
+		var item shopping.Item
+		if item, err = api.ParseShoppingItem(r.URL.Query().Get("item")); err != nil {
+			errMsg := fmt.Sprintf("Malformed Query, invalid `item` parameter: %s", err)
+			http.Error(w, errMsg, http.StatusUnprocessableEntity)
+			return
+		}
+		update = func(list *shopping.List) error {
+			list.AddItem(item)
+			return nil
+		}
+
+	case http.MethodDelete:
+		id, ok := shoppingItemID(w, r)
+		if !ok {
+			return
+		}
+		update = func(list *shopping.List) error {
+			return list.RemoveItem(id)
+		}
+
+	default:
+		http.Error(w, "Method is not supported.", http.StatusNotFound)
+		return
+	}
+
+	var list shopping.List
+	if list, err = api.UpdateShoppingList(name, update); err != nil {
+		writeShoppingError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Reads the `name` parameter of a shopping list request, responding with an
+// error if it is missing or invalid.
+func shoppingListName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Malformed Query, missing `name` parameter.", http.StatusUnprocessableEntity)
+		return "", false
+	}
+	if _, err := shopping.ListPath(config.GetConfig().Shopping.Dir, name); err != nil {
+		errMsg := fmt.Sprintf("Malformed Query, invalid `name` parameter: %s", err)
+		http.Error(w, errMsg, http.StatusUnprocessableEntity)
+		return "", false
+	}
+	return name, true
+}
+
+// Reads the `id` parameter of a shopping list request, responding with an
+// error if it is missing or invalid.
+func shoppingItemID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Malformed Query, invalid `id` parameter.", http.StatusUnprocessableEntity)
+		return 0, false
+	}
+	return id, true
+}
+
+// Responds with the error of a shopping list request, as not found if the
+// list or item doesn't exist.
+func writeShoppingError(w http.ResponseWriter, err error) {
+	if errors.Is(err, shopping.ErrListNotFound) || errors.Is(err, shopping.ErrItemNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
+
+// Responds with `v` encoded as JSON
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, "Failed to encode response.", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(jsonBytes)
+}
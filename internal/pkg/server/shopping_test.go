@@ -0,0 +1,150 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestShoppingLists(t *testing.T) {
+	recipes := loadTestConfig(t)
+	pancakes := "Mix @flour{2%cups}, @milk{1%cup} and @eggs{2}.\n"
+	if err := os.WriteFile(filepath.Join(recipes, "pancakes.cook"), []byte(pancakes), 0644); err != nil {
+		t.Fatal(err)
+	}
+	crepes := "Fill with @./nutella{}.\n"
+	if err := os.WriteFile(filepath.Join(recipes, "crepes.cook"), []byte(crepes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(handler http.HandlerFunc, method string, params url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/0/shopping/?"+params.Encode(), nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	testStatus := func(handler http.HandlerFunc, method string, params url.Values, want int) {
+		if got := request(handler, method, params).Code; got != want {
+			t.Fatalf("Wrong status for %v %v\ngot: %v\nwant: %v.", method, params, got, want)
+		}
+	}
+	getList := func(rec *httptest.ResponseRecorder) shopping.List {
+		var list shopping.List
+		if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+			t.Fatalf("Invalid list: %v\n%v", err, rec.Body.String())
+		}
+		return list
+	}
+	week := url.Values{"name": {"week"}}
+
+	// Creating
+	rec := request(apiShoppingLists, http.MethodPost,
+		url.Values{"name": {"week"}, "recipe": {"pancakes"}})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Create failed: %v %v", rec.Code, rec.Body.String())
+	}
+	if list := getList(rec); len(list.Items) != 3 {
+		t.Fatalf("Wrong items: %+v", list.Items)
+	}
+	testStatus(apiShoppingLists, http.MethodPost,
+		url.Values{"name": {"week"}, "recipe": {"pancakes"}}, http.StatusConflict)
+	testStatus(apiShoppingLists, http.MethodPost,
+		url.Values{"name": {"other"}, "recipe": {"waffles"}}, http.StatusNotFound)
+	testStatus(apiShoppingLists, http.MethodPost,
+		url.Values{"name": {"other"}, "recipe": {"pancakes:x"}}, http.StatusUnprocessableEntity)
+	testStatus(apiShoppingLists, http.MethodPost,
+		url.Values{"name": {"other"}, "recipe": {"crepes"}}, http.StatusUnprocessableEntity)
+	testStatus(apiShoppingLists, http.MethodPost,
+		url.Values{"name": {"../week"}, "recipe": {"pancakes"}}, http.StatusUnprocessableEntity)
+
+	// Failures reading the pantry aren't the request's fault
+	pantryPath := filepath.Join(filepath.Dir(recipes), "pantry.toml")
+	if err := os.WriteFile(pantryPath, []byte("not = [toml"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	testStatus(apiShoppingLists, http.MethodPost,
+		url.Values{"name": {"other"}, "recipe": {"pancakes"}}, http.StatusInternalServerError)
+	if err := os.Remove(pantryPath); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = request(apiShoppingLists, http.MethodGet, url.Values{})
+	if rec.Body.String() != `["week"]` {
+		t.Fatalf("Wrong list names\ngot: %v\nwant: %v.", rec.Body.String(), `["week"]`)
+	}
+
+	// Checking off items from two phones at once must not lose either
+	list := getList(request(apiShoppingList, http.MethodGet, week))
+	var wg sync.WaitGroup
+	for _, item := range list.Items {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			params := url.Values{"name": {"week"}, "id": {strconv.Itoa(id)}, "checked": {"true"}}
+			request(apiShoppingList, http.MethodPatch, params)
+		}(item.ID)
+	}
+	wg.Wait()
+	for _, item := range getList(request(apiShoppingList, http.MethodGet, week)).Items {
+		if !item.Checked {
+			t.Fatalf("Check-off was lost: %+v", item)
+		}
+	}
+
+	params := url.Values{"name": {"week"}, "id": {"1"}, "checked": {"false"}}
+	if list := getList(request(apiShoppingList, http.MethodPatch, params)); list.Items[0].Checked {
+		t.Fatalf("Item was not unchecked: %+v", list.Items[0])
+	}
+
+	// Free-form items
+	rec = request(apiShoppingListItems, http.MethodPost,
+		url.Values{"name": {"week"}, "item": {"2 litres orange juice"}})
+	list = getList(rec)
+	added := list.Items[len(list.Items)-1]
+	want := shopping.Item{ID: 4, Name: "orange juice", Aisle: shopping.OtherAisle,
+		Amounts: []shopping.Amount{{Qty: 2, Unit: "litres"}}}
+	if added.ID != want.ID || added.Name != want.Name || added.AmountText() != want.AmountText() {
+		t.Fatalf("Wrong item added\ngot: %+v\nwant: %+v.", added, want)
+	}
+
+	testStatus(apiShoppingListItems, http.MethodDelete,
+		url.Values{"name": {"week"}, "id": {"4"}}, http.StatusOK)
+	testStatus(apiShoppingListItems, http.MethodDelete,
+		url.Values{"name": {"week"}, "id": {"4"}}, http.StatusNotFound)
+
+	// IDs are never reused
+	list = getList(request(apiShoppingListItems, http.MethodPost,
+		url.Values{"name": {"week"}, "item": {"bread"}}))
+	if id := list.Items[len(list.Items)-1].ID; id != 5 {
+		t.Fatalf("Wrong ID for added item\ngot: %v\nwant: %v.", id, 5)
+	}
+
+	// Failures
+	testStatus(apiShoppingList, http.MethodPatch,
+		url.Values{"name": {"week"}, "id": {"x"}, "checked": {"true"}}, http.StatusUnprocessableEntity)
+	testStatus(apiShoppingList, http.MethodPatch,
+		url.Values{"name": {"week"}, "id": {"1"}, "checked": {"maybe"}}, http.StatusUnprocessableEntity)
+	testStatus(apiShoppingList, http.MethodPatch,
+		url.Values{"name": {"week"}, "id": {"99"}, "checked": {"true"}}, http.StatusNotFound)
+	testStatus(apiShoppingList, http.MethodGet, url.Values{"name": {"missing"}}, http.StatusNotFound)
+	testStatus(apiShoppingList, http.MethodPost, week, http.StatusNotFound)
+	testStatus(apiShoppingListItems, http.MethodPost,
+		url.Values{"name": {"week"}, "item": {" "}}, http.StatusUnprocessableEntity)
+
+	// Deleting
+	testStatus(apiShoppingList, http.MethodDelete, week, http.StatusNoContent)
+	testStatus(apiShoppingList, http.MethodDelete, week, http.StatusNotFound)
+	testStatus(apiShoppingList, http.MethodGet, week, http.StatusNotFound)
+}
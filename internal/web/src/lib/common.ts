@@ -18,18 +18,47 @@ export interface Component {
     unit:   string;
 }
 
+export interface Temperature {
+    value:  number;
+    scale:  string;
+    raw:    string;
+}
+
 export interface Chunk {
     tag: string;
-    data: Component | string;
+    data: Component | Temperature | string;
+}
+
+export interface StepManifest {
+    ingredients:    [number];
+    cookware:       [number];
+    timers:         [number];
+}
+
+export interface Nutrients {
+    calories:   number;
+    protein:    number;
+    fat:        number;
+    carbs:      number;
+    fibre:      number;
+}
+
+export interface Nutrition {
+    total:          Nutrients;
+    servings?:      number;
+    perServing?:    Nutrients;
+    unmatched:      { name: string; reason: string }[];
 }
 
 export interface Recipe {
     name:           string;
-    metadata:       { tag: string, body: string };
+    metadata:       { [key: string]: string | string[] };
     ingredients:    [Component];
     cookware:       [Component];
     timers:         [Component];
     steps:          [[Chunk]];
+    stepManifests:  [StepManifest];
+    nutrition?:     Nutrition;
 }
 
 export function stripRecipeName(name: string): string {
@@ -0,0 +1,168 @@
+package cook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// A single metadata key alongside every value it was given, in source order.
+type MetadataEntry struct {
+	Key    string
+	Values []string
+}
+
+// Metadata is an ordered list of the `>> key: value` lines of a recipe.
+//
+// Keys are kept in the order they first appear, and repeated keys accumulate
+// their values rather than overwriting each other.
+// e.g.
+//
+//	>> tag: breakfast
+//	>> servings: 2
+//	>> tag: sweet
+//
+// results in `[{tag [breakfast sweet]} {servings [2]}]`
+type Metadata []MetadataEntry
+
+// Returns the index of the entry with `key`, or -1 if it does not exist.
+func (m Metadata) index(key string) int {
+	for i, entry := range m {
+		if entry.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Returns the first value defined for `key`.
+//
+// The bool is false if the key does not exist.
+func (m Metadata) Get(key string) (string, bool) {
+	i := m.index(key)
+	if i < 0 || len(m[i].Values) == 0 {
+		return "", false
+	}
+	return m[i].Values[0], true
+}
+
+// Returns every value defined for `key`, in order. nil if the key does not exist.
+func (m Metadata) GetAll(key string) []string {
+	i := m.index(key)
+	if i < 0 {
+		return nil
+	}
+	return m[i].Values
+}
+
+// Tests for the existence of `key`
+func (m Metadata) Has(key string) bool {
+	return m.index(key) >= 0
+}
+
+// Returns each key, in the order they were first defined.
+func (m Metadata) Keys() []string {
+	keys := make([]string, len(m))
+	for i, entry := range m {
+		keys[i] = entry.Key
+	}
+	return keys
+}
+
+// Appends `value` to `key`, creating the entry if it does not exist yet.
+func (m *Metadata) Add(key string, value string) {
+	if i := m.index(key); i >= 0 {
+		(*m)[i].Values = append((*m)[i].Values, value)
+		return
+	}
+	*m = append(*m, MetadataEntry{Key: key, Values: []string{value}})
+}
+
+// Replaces all values of `key` with `value`, creating the entry if needed.
+func (m *Metadata) Set(key string, value string) {
+	if i := m.index(key); i >= 0 {
+		(*m)[i].Values = []string{value}
+		return
+	}
+	m.Add(key, value)
+}
+
+// Custom JSON encoding writes metadata as an object with keys in their
+// original order. Keys with a single value map to a string, repeated keys map
+// to a list of strings.
+//
+// e.g. `{"tag": ["breakfast", "sweet"], "servings": "2"}`
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, entry := range m {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		var val []byte
+		if len(entry.Values) == 1 {
+			val, err = json.Marshal(entry.Values[0])
+		} else {
+			val, err = json.Marshal(entry.Values)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// Decodes the object created by the encoder, keeping the order of its keys.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	// Expect an object
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return errors.New("Metadata must be a JSON object.")
+	}
+
+	meta := Metadata{}
+	for dec.More() {
+		// Keys are always strings
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+
+		// Values are either a string or a list of strings
+		var raw json.RawMessage
+		if err = dec.Decode(&raw); err != nil {
+			return err
+		}
+		var values []string
+		if err = json.Unmarshal(raw, &values); err != nil {
+			var value string
+			if err = json.Unmarshal(raw, &value); err != nil {
+				return err
+			}
+			values = []string{value}
+		}
+
+		for _, value := range values {
+			meta.Add(key, value)
+		}
+	}
+
+	*m = meta
+	return nil
+}
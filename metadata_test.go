@@ -0,0 +1,63 @@
+package cook
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestMetadataAccumulates(t *testing.T) {
+	r := ParseRecipeString("", `>> tag: breakfast
+>> servings: 2
+>> tag: sweet
+`)
+
+	want := Metadata{
+		{Key: "tag", Values: []string{"breakfast", "sweet"}},
+		{Key: "servings", Values: []string{"2"}},
+	}
+	if !reflect.DeepEqual(r.Metadata, want) {
+		t.Fatalf("Metadata mismatch:\ngot: %+v\nwant: %+v.", r.Metadata, want)
+	}
+
+	if v, ok := r.Metadata.Get("tag"); !ok || v != "breakfast" {
+		t.Fatalf("Get returned %v, %v", v, ok)
+	}
+	if _, ok := r.Metadata.Get("missing"); ok {
+		t.Fatalf("Get found a missing key")
+	}
+	if keys := r.Metadata.Keys(); !reflect.DeepEqual(keys, []string{"tag", "servings"}) {
+		t.Fatalf("Keys out of order: %v", keys)
+	}
+}
+
+func TestMetadataJSON(t *testing.T) {
+	meta := Metadata{}
+	meta.Add("servings", "2")
+	meta.Add("tag", "breakfast")
+	meta.Add("tag", "sweet")
+	meta.Add("author", "me")
+
+	// Key order must be stable
+	data, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"servings":"2","tag":["breakfast","sweet"],"author":"me"}`
+	if string(data) != want {
+		t.Fatalf("Encoding mismatch:\ngot: %s\nwant: %s.", data, want)
+	}
+
+	// Decoding must be lossless
+	var got Metadata
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, meta) {
+		t.Fatalf("JSON round trip failed:\ngot: %+v\nwant: %+v.", got, meta)
+	}
+}
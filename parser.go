@@ -98,10 +98,18 @@ func buildCookY(ast *y.AST) y.Parser {
 		},
 		word, mwComponentText, amountField)
 
+	//-------------
+	// Recipe References
+	//-------------
+	// A relative path to another recipe, e.g. `@./sauces/hollandaise{150%g}`
+	// (this is an extension to the linked spec)
+	refPath := y.TokenExact(`\.\.?/[^{~@#\r\n]+`, "REF_PATH")
+	refComponent := ast.And("recipe_ref_component", nil, refPath, amountField)
+
 	//-------------
 	// Ingredients
 	//-------------
-	ingredientTypes := ast.OrdChoice("", nil, mwComponent, owComponent)
+	ingredientTypes := ast.OrdChoice("", nil, refComponent, mwComponent, owComponent)
 	ingredient := ast.And("ingredient", nil, at, ingredientTypes)
 
 	//-------------
@@ -224,6 +232,9 @@ func parseComponentNode(node y.Queryable) Component {
 	case "multiword_component":
 		text = children[0].GetValue() + children[1].GetValue()
 		amountNode = children[2]
+	case "recipe_ref_component":
+		text = strings.TrimSpace(children[0].GetValue())
+		amountNode = children[1]
 	default:
 		panic("Unknown node found while parsing component.")
 	}
@@ -275,12 +286,13 @@ func ParseRecipeString(name string, data string) Recipe {
 // and returns as a `Recipe` struct
 func ParseRecipe(name string, data *[]byte) Recipe {
 	r := Recipe{
-		Name:        name,
-		Metadata:    map[string]string{},
-		Ingredients: []Ingredient{},
-		Cookware:    []Cookware{},
-		Timers:      []Timer{},
-		Steps:       []Step{},
+		Name:          name,
+		Metadata:      Metadata{},
+		Ingredients:   []Ingredient{},
+		Cookware:      []Cookware{},
+		Timers:        []Timer{},
+		Steps:         []Step{},
+		StepManifests: []StepManifest{},
 	}
 
 	// Don't parse empty recipe
@@ -309,23 +321,28 @@ func ParseRecipe(name string, data *[]byte) Recipe {
 		switch node.GetName() {
 		case "metadata":
 			// Metadata is super simple, just push to recipe
+			// (repeated tags accumulate, rather than overwrite)
 			children := node.GetChildren()
 			tag := strings.TrimSpace(children[1].GetValue())
 			val := strings.TrimSpace(children[3].GetValue())
-			r.Metadata[tag] = val
+			r.Metadata.Add(tag, val)
 		case "step":
 			// Steps are built from chunks, we need to parse those
 			step := make(Step, 0)
+			manifest := newStepManifest()
 			stepSubNodes := node.GetChildren()
 			for i, chunkNode := range stepSubNodes {
 				chunk := parseChunkNode(chunkNode)
 
 				switch chunk := chunk.(type) {
 				case Ingredient:
+					manifest.Ingredients = append(manifest.Ingredients, len(r.Ingredients))
 					r.Ingredients = append(r.Ingredients, chunk)
 				case Cookware:
+					manifest.Cookware = append(manifest.Cookware, len(r.Cookware))
 					r.Cookware = append(r.Cookware, chunk)
 				case Timer:
+					manifest.Timers = append(manifest.Timers, len(r.Timers))
 					r.Timers = append(r.Timers, chunk)
 				case Text:
 					// Join consecutive text blocks together
@@ -345,6 +362,7 @@ func ParseRecipe(name string, data *[]byte) Recipe {
 			// Push newly built step into the recipe
 			if len(step) > 0 {
 				r.Steps = append(r.Steps, step)
+				r.StepManifests = append(r.StepManifests, manifest)
 			}
 		default:
 			panic("Unhandled node returned from query.")
@@ -43,6 +43,29 @@ func TestTryParseQty(t *testing.T) {
 	testFrac("NoQty", NoQty) // Float keyword
 }
 
+func TestParseRecipeRef(t *testing.T) {
+	testRef := func(in string, want Ingredient) {
+		r := ParseRecipeString("", in)
+		if len(r.Ingredients) != 1 || r.Ingredients[0] != want {
+			t.Fatalf("Failed to parse: \"%s\"\ngot: %+v\nwant: %+v.", in, r.Ingredients, want)
+		}
+		if !r.Ingredients[0].IsRecipeRef() {
+			t.Fatalf("Not a recipe reference: \"%s\"", in)
+		}
+	}
+
+	testRef("Pour @./sauces/hollandaise{150%g} over",
+		Ingredient{Name: "./sauces/hollandaise", Qty: "150", QtyVal: 150, Unit: "g"})
+	testRef("Add @../bases/pizza dough{2}",
+		Ingredient{Name: "../bases/pizza dough", Qty: "2", QtyVal: 2, Unit: ""})
+
+	// Specifiers end a reference
+	r := ParseRecipeString("", "Add @./dough and @eggs{2}")
+	if len(r.Ingredients) != 1 || r.Ingredients[0].Name != "eggs" {
+		t.Fatalf("Reference consumed an ingredient: %+v", r.Ingredients)
+	}
+}
+
 // --------------------------------------------------------------
 // Examples
 // --------------------------------------------------------------
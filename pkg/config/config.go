@@ -21,11 +21,19 @@ var conf Config
 // Internal representations for configs, used to (de)serialize to toml
 type (
 	Config struct {
-		Units    string         `toml:"units"`
-		Recipe   RecipeConfig   `toml:"recipe"`
-		Shopping ShoppingConfig `toml:"shopping"`
-		Users    string         `toml:"users"`
-		HMACKey  string         `toml:"hmac-key"`
+		Units     string          `toml:"units"`
+		Recipe    RecipeConfig    `toml:"recipe"`
+		Shopping  ShoppingConfig  `toml:"shopping"`
+		Lint      LintConfig      `toml:"lint"`
+		Export    ExportConfig    `toml:"export"`
+		Calendar  CalendarConfig  `toml:"calendar"`
+		Schedule  ScheduleConfig  `toml:"schedule"`
+		Nutrition NutritionConfig `toml:"nutrition"`
+		Import    ImportConfig    `toml:"import"`
+		Users     string          `toml:"users"`
+		Pantry    string          `toml:"pantry"`
+		Plan      string          `toml:"plan"`
+		HMACKey   string          `toml:"hmac-key"`
 	}
 
 	RecipeConfig struct {
@@ -35,6 +43,47 @@ type (
 	ShoppingConfig struct {
 		Dir string `toml:"dir"`
 	}
+
+	// Severities of each `cook check` rule, keyed by rule name.
+	// e.g. `unknown-unit = "error"`. Rules default to "warning".
+	LintConfig struct {
+		Rules map[string]string `toml:"rules"`
+	}
+
+	// A directory of `html/template` files which override the built-in export
+	// templates (by file name, e.g. `recipe.html`). Built-ins are used if empty.
+	ExportConfig struct {
+		TemplateDir string `toml:"template-dir"`
+	}
+
+	// The meal plan's calendar feed. Meals start at `meal-times` (e.g.
+	// `dinner = "18:30"`) and last `duration`, defaults are used if empty.
+	// `url` is the webapp's address, which events link to.
+	CalendarConfig struct {
+		URL       string            `toml:"url"`
+		Duration  string            `toml:"duration"`
+		MealTimes map[string]string `toml:"meal-times"`
+	}
+
+	// Cookware which `cook schedule` lets only one step use at a time, e.g.
+	// `exclusive-cookware = ["oven", "stand mixer"]`. Just the oven if unset.
+	ScheduleConfig struct {
+		ExclusiveCookware []string `toml:"exclusive-cookware"`
+	}
+
+	// The nutrient table (CSV), its overrides (CSV) and the aliases mapping
+	// ingredient names to its foods, see `NutritionPaths` for defaults.
+	NutritionConfig struct {
+		Table     string `toml:"table"`
+		Overrides string `toml:"overrides"`
+		Aliases   string `toml:"aliases"`
+	}
+
+	// Whether pages on loopback, link-local or private addresses (e.g. a
+	// recipe manager on the local network) may be imported. Off by default.
+	ImportConfig struct {
+		AllowPrivate bool `toml:"allow-private"`
+	}
 )
 
 // Returns a copy of the config, this should be
@@ -69,6 +118,8 @@ func LoadConfig(path string) bool {
 		return false
 	}
 
+	// Start afresh, so nothing is kept from a previously loaded config
+	conf = Config{}
 	_, err := toml.DecodeFile(path, &conf)
 	if err != nil {
 		panic(err)
@@ -132,6 +183,8 @@ func ConfigInit(path string, recipes string, shopping string) bool {
 	conf.Recipe.Dir = recipes
 	conf.Shopping.Dir = shopping
 	conf.Users = defaultUsersPath()
+	conf.Pantry = filepath.Join(filepath.Dir(shopping), "pantry.toml")
+	conf.Plan = filepath.Join(filepath.Dir(shopping), "plan.toml")
 	conf.HMACKey = string(generateHMACKey(128))
 
 	loaded = true
@@ -165,6 +218,68 @@ func DefaultConfigPath() string {
 	return path
 }
 
+// Returns the path of the aisle configuration (`aisle.conf`), which sits next
+// to the loaded config file.
+//
+// Panics if used before a load
+func AislePath() string {
+	if !loaded {
+		panic("Attempted to read an unloaded config")
+	}
+	return filepath.Join(filepath.Dir(configPath), "aisle.conf")
+}
+
+// Returns the path of the pantry file, defaulting to `pantry.toml` alongside
+// the shopping directory when the config doesn't set one.
+//
+// Panics if used before a load
+func PantryPath() string {
+	if !loaded {
+		panic("Attempted to read an unloaded config")
+	}
+	if conf.Pantry != "" {
+		return conf.Pantry
+	}
+	return filepath.Join(filepath.Dir(conf.Shopping.Dir), "pantry.toml")
+}
+
+// Returns the path of the meal plan file, defaulting to `plan.toml` alongside
+// the shopping directory when the config doesn't set one.
+//
+// Panics if used before a load
+func PlanPath() string {
+	if !loaded {
+		panic("Attempted to read an unloaded config")
+	}
+	if conf.Plan != "" {
+		return conf.Plan
+	}
+	return filepath.Join(filepath.Dir(conf.Shopping.Dir), "plan.toml")
+}
+
+// Returns the paths of the nutrition files, defaulting to `nutrition.csv`,
+// `nutrition-overrides.csv` and `nutrition-aliases.conf` alongside the loaded
+// config file when the config doesn't set them.
+//
+// Panics if used before a load
+func NutritionPaths() NutritionConfig {
+	if !loaded {
+		panic("Attempted to read an unloaded config")
+	}
+	paths := conf.Nutrition
+	dir := filepath.Dir(configPath)
+	if paths.Table == "" {
+		paths.Table = filepath.Join(dir, "nutrition.csv")
+	}
+	if paths.Overrides == "" {
+		paths.Overrides = filepath.Join(dir, "nutrition-overrides.csv")
+	}
+	if paths.Aliases == "" {
+		paths.Aliases = filepath.Join(dir, "nutrition-aliases.conf")
+	}
+	return paths
+}
+
 // Returns the default data path defined on a system
 // TODO: windows support
 func defaultDataPath(target string) string {
@@ -0,0 +1,179 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+)
+
+// The formats books can be exported as
+const (
+	BookLaTeX = "latex"
+	BookTypst = "typst"
+)
+
+// Options for book exports
+type BookOptions struct {
+	// The format of the book, `BookLaTeX` or `BookTypst`
+	Format string
+	// The book's title, defaults to the name of the exported directory
+	Title string
+	// Multiplies every quantity, ignored if 0
+	Scale float64
+	// Scales each recipe (with `servings` metadata) to serve this many,
+	// ignored if 0. Applied before `Scale`.
+	Servings float64
+	// Options passed on to chunk rendering (e.g. temperature units)
+	Render recipe.RenderOptions
+}
+
+// Scales a recipe's quantities (and servings) as set in `opts`
+func scaleBookRecipe(r *cook.Recipe, opts BookOptions) {
+	factor := 1.0
+	servings, hasServings := cook.RecipeServings(r)
+	if opts.Servings > 0 && hasServings {
+		factor = opts.Servings / servings
+	}
+	if opts.Scale > 0 {
+		factor *= opts.Scale
+	}
+	if factor == 1 {
+		return
+	}
+
+	cook.ScaleRecipe(r, factor)
+	if hasServings {
+		r.Metadata.Set("servings", cook.FormatQty(servings*factor))
+	}
+}
+
+// Returns the title of a chapter, e.g. "mains/pasta" -> "Mains / Pasta".
+// Recipes at the root are gathered under "Recipes".
+func chapterTitle(folder string) string {
+	if folder == "" {
+		return "Recipes"
+	}
+
+	parts := strings.Split(folder, "/")
+	for i, part := range parts {
+		part = recipe.FilepathToName(part)
+		first, size := utf8.DecodeRuneInString(part)
+		parts[i] = string(unicode.ToUpper(first)) + part[size:]
+	}
+	return strings.Join(parts, " / ")
+}
+
+// Exports a recipe, or every recipe within a directory (recursively), as the
+// source of a printable book (LaTeX or Typst) written to `w`.
+//
+// A directory becomes one document with a table of contents and a chapter per
+// folder, each recipe laid out with its ingredients beside its steps. A lone
+// recipe becomes a standalone document.
+func ExportBook(src string, w io.Writer, opts BookOptions) error {
+	var err error
+
+	if opts.Format != BookLaTeX && opts.Format != BookTypst {
+		return fmt.Errorf("Unknown book format %q, expected %v or %v",
+			opts.Format, BookLaTeX, BookTypst)
+	}
+	if !common.FileExists(src) {
+		return errors.New("File not found: " + src)
+	}
+
+	if info, _ := os.Stat(src); !info.IsDir() {
+		var r *cook.Recipe
+		if r, err = parseRecipeFile(src, opts.Render); err != nil {
+			return err
+		}
+		scaleBookRecipe(r, opts)
+
+		var renderer recipe.Renderer
+		if renderer, err = recipe.GetRenderer(opts.Format); err != nil {
+			return err
+		}
+		return renderer.Render(w, r, opts.Render)
+	}
+
+	var names []string
+	if names, err = collectRecipes(src); err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return errors.New("No recipes found in: " + src)
+	}
+
+	if opts.Title == "" {
+		var absSrc string
+		if absSrc, err = filepath.Abs(src); err != nil {
+			return err
+		}
+		opts.Title = recipe.FilepathToName(absSrc)
+	}
+
+	// Recipes grouped by folder, root recipes first
+	chapters := make([]string, 0)
+	byChapter := map[string][]string{}
+	for _, name := range names {
+		folder := folderOf(name)
+		if _, ok := byChapter[folder]; !ok {
+			chapters = append(chapters, folder)
+		}
+		byChapter[folder] = append(byChapter[folder], name)
+	}
+	sort.Strings(chapters)
+
+	var sb strings.Builder
+	switch opts.Format {
+	case BookLaTeX:
+		sb.WriteString(recipe.LaTeXPreamble("book", opts.Title))
+		sb.WriteString("\\maketitle\n\\tableofcontents\n")
+	case BookTypst:
+		sb.WriteString(recipe.TypstPreamble(opts.Title))
+		sb.WriteString(fmt.Sprintf("#align(center)[#text(size: 2em)[%v]]\n\n",
+			recipe.TypstEscape(opts.Title)))
+		sb.WriteString("#outline(depth: 2)\n")
+	}
+
+	for _, chapter := range chapters {
+		switch opts.Format {
+		case BookLaTeX:
+			sb.WriteString(fmt.Sprintf("\n\\chapter{%v}\n", recipe.LaTeXEscape(chapterTitle(chapter))))
+		case BookTypst:
+			sb.WriteString(fmt.Sprintf("\n#pagebreak()\n= %v\n", recipe.TypstEscape(chapterTitle(chapter))))
+		}
+
+		for _, name := range byChapter[chapter] {
+			var r *cook.Recipe
+			p := filepath.Join(src, filepath.FromSlash(name)+".cook")
+			if r, err = parseRecipeFile(p, opts.Render); err != nil {
+				return err
+			}
+			scaleBookRecipe(r, opts)
+
+			sb.WriteString("\n")
+			switch opts.Format {
+			case BookLaTeX:
+				sb.WriteString(recipe.LaTeXRecipe(r, opts.Render))
+			case BookTypst:
+				sb.WriteString(recipe.TypstRecipe(r, opts.Render, 2))
+			}
+		}
+	}
+
+	if opts.Format == BookLaTeX {
+		sb.WriteString("\n\\end{document}\n")
+	}
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
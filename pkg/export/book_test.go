@@ -0,0 +1,50 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestExportBook(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "toast.cook", ">> servings: 2\nToast @bread{2%slices} & add @salt_&_pepper{}.")
+	writeTestFile(t, dir, "sauces/hollandaise.cook", "Whisk @egg yolks{3} in a #bowl{}.")
+
+	testBook := func(opts BookOptions, want ...string) {
+		var sb strings.Builder
+		if err := ExportBook(dir, &sb, opts); err != nil {
+			t.Fatalf("Failed to export %v: %v", opts.Format, err)
+		}
+		for _, text := range want {
+			if !strings.Contains(sb.String(), text) {
+				t.Fatalf("Missing from %v book\ngot:\n%v\nwant: %q.", opts.Format, sb.String(), text)
+			}
+		}
+	}
+
+	testBook(BookOptions{Format: BookLaTeX, Title: "Mom's #1", Servings: 4},
+		`\title{Mom's \#1}`,
+		`\chapter{Recipes}`,
+		`\chapter{Sauces}`,
+		`\textbf{servings:} 4`,
+		`\item 4 slices bread`,
+		`\item salt\_\&\_pepper`,
+		`\emph{bowl}`,
+		`\switchcolumn`,
+		`\end{document}`)
+
+	testBook(BookOptions{Format: BookTypst, Scale: 0.5},
+		`= Sauces`,
+		`== toast`,
+		`- 1 slices bread`,
+		`#strong[salt\_&\_pepper];`,
+		`#emph[bowl];`)
+
+	if err := ExportBook(dir, &strings.Builder{}, BookOptions{Format: "pdf"}); err == nil {
+		t.Fatalf("Exported an unknown format")
+	}
+}
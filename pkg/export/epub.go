@@ -0,0 +1,423 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	textTemplate "text/template"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+)
+
+// The templates and stylesheet making up an EPUB
+//
+//go:embed epub/*
+var epubFiles embed.FS
+
+// Options for EPUB exports
+type EPUBOptions struct {
+	// The book's title, defaults to the name of the exported directory
+	Title string
+	// Options passed on to chunk rendering (e.g. temperature units)
+	Render recipe.RenderOptions
+}
+
+// An entry in the table of contents, either a recipe or a folder of entries
+type TOCEntry struct {
+	Name     string
+	Href     string
+	Children []TOCEntry
+}
+
+// The data passed to the `nav.xhtml` template
+type NavPage struct {
+	Title    string
+	Contents []TOCEntry
+}
+
+// The data passed to the `recipe.xhtml` template
+type EPUBRecipePage struct {
+	Title  string
+	Recipe *cook.Recipe
+	Steps  [][]ChunkView
+	Image  string // Relative link to the recipe's photo, if it has one
+	Style  string // Relative link to the stylesheet
+}
+
+// An ingredient and the recipes using it
+type IndexEntry struct {
+	Name    string
+	Recipes []LinkView
+}
+
+// The ingredients starting with a letter
+type IndexLetter struct {
+	Letter  string
+	Entries []IndexEntry
+}
+
+// The data passed to the `ingredients.xhtml` template
+type IngredientIndexPage struct {
+	Letters []IndexLetter
+}
+
+// A file within the EPUB package
+type epubItem struct {
+	ID         string
+	Href       string // Relative to the package document, URL escaped
+	MediaType  string
+	Properties string
+}
+
+// The data passed to the `content.opf` template
+type packagePage struct {
+	Identifier string
+	Title      string
+	Modified   string
+	Items      []epubItem
+	Spine      []string // IDs of the items, in reading order
+}
+
+// Media types of the images which may be bundled, by extension
+var imageMediaTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// Finds the photo sharing a recipe's name (e.g. `toast.jpg` for `toast.cook`),
+// returning its path or "" if it has none.
+func siblingImage(recipePath string) string {
+	base := strings.TrimSuffix(recipePath, filepath.Ext(recipePath))
+	exts := make([]string, 0, len(imageMediaTypes))
+	for ext := range imageMediaTypes {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+
+	for _, ext := range exts {
+		for _, candidate := range []string{base + ext, base + strings.ToUpper(ext)} {
+			if common.FileExists(candidate) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// Builds the table of contents from recipe names, nesting recipes within
+// their folders. Each folder lists its recipes before its subfolders.
+func tocEntries(names []string, href func(name string) string) []TOCEntry {
+	entries := make([]TOCEntry, 0)
+	folders := map[string][]string{}
+	folderOrder := make([]string, 0)
+	for _, name := range names {
+		first, rest, nested := strings.Cut(name, "/")
+		if !nested {
+			entries = append(entries, TOCEntry{Name: recipe.FilepathToName(name), Href: href(name)})
+			continue
+		}
+		if _, ok := folders[first]; !ok {
+			folderOrder = append(folderOrder, first)
+		}
+		folders[first] = append(folders[first], rest)
+	}
+
+	for _, folder := range folderOrder {
+		prefixed := func(name string) string { return href(folder + "/" + name) }
+		entries = append(entries, TOCEntry{
+			Name:     folder,
+			Children: tocEntries(folders[folder], prefixed),
+		})
+	}
+	return entries
+}
+
+// Returns the recipe names in the order of the table of contents
+func tocOrder(entries []TOCEntry, names map[string]string) []string {
+	order := make([]string, 0)
+	for _, entry := range entries {
+		if entry.Children != nil {
+			order = append(order, tocOrder(entry.Children, names)...)
+		} else {
+			order = append(order, names[entry.Href])
+		}
+	}
+	return order
+}
+
+// Builds the ingredient index, grouped by first letter. Recipe references
+// aren't ingredients, so they are left out.
+func ingredientIndex(names []string, recipes map[string]*cook.Recipe, href func(name string) string) []IndexLetter {
+	entries := map[string]*IndexEntry{}
+	for _, name := range names {
+		seen := map[string]bool{}
+		for _, ingr := range recipes[name].Ingredients {
+			key := strings.ToLower(strings.TrimSpace(ingr.Name))
+			if key == "" || ingr.IsRecipeRef() || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			entry, ok := entries[key]
+			if !ok {
+				entry = &IndexEntry{Name: strings.TrimSpace(ingr.Name)}
+				entries[key] = entry
+			}
+			entry.Recipes = append(entry.Recipes, LinkView{
+				Name: recipes[name].Name,
+				Href: href(name),
+			})
+		}
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	letters := make([]IndexLetter, 0)
+	for _, key := range keys {
+		first, _ := utf8.DecodeRuneInString(key)
+		letter := "#"
+		if unicode.IsLetter(first) {
+			letter = string(unicode.ToUpper(first))
+		}
+
+		if len(letters) == 0 || letters[len(letters)-1].Letter != letter {
+			letters = append(letters, IndexLetter{Letter: letter})
+		}
+		last := &letters[len(letters)-1]
+		last.Entries = append(last.Entries, *entries[key])
+	}
+	return letters
+}
+
+// Formats a hash as a (name based) UUID URN, so re-exports of the same
+// recipes keep their identifier.
+func bookIdentifier(hash string) string {
+	return fmt.Sprintf("urn:uuid:%v-%v-5%v-8%v-%v",
+		hash[0:8], hash[8:12], hash[13:16], hash[17:20], hash[20:32])
+}
+
+// Escapes each segment of a slash-separated path for use within a URL
+func escapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// Writes a file into the zip, compressed unless `store` is set
+func writeZipFile(zw *zip.Writer, name string, data []byte, store bool) error {
+	method := zip.Deflate
+	if store {
+		method = zip.Store
+	}
+
+	header := &zip.FileHeader{Name: name, Method: method}
+	// A modification time adds an extra field to the header, which the OCF
+	// container forbids on the mimetype
+	if name != "mimetype" {
+		header.Modified = time.Now()
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// A template which can be executed by name, either `html/template` or
+// `text/template`
+type namedTemplate interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+// Executes `tmplName` with `data` and writes the result into the zip
+func writeZipTemplate(zw *zip.Writer, tmpl namedTemplate, tmplName string, name string, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, tmplName, data); err != nil {
+		return fmt.Errorf("Failed to render %v: %w", name, err)
+	}
+	return writeZipFile(zw, name, buf.Bytes(), false)
+}
+
+// Exports a recipe, or every recipe within a directory (recursively), as an
+// EPUB 3 cookbook written to `w`.
+//
+// The book holds a page per recipe (along with its photo, if a sibling image
+// shares its name), a table of contents following the folder hierarchy and an
+// ingredient index at the back.
+func ExportEPUB(src string, w io.Writer, opts EPUBOptions) error {
+	var err error
+
+	if !common.FileExists(src) {
+		return errors.New("File not found: " + src)
+	}
+
+	// The package document isn't HTML, so it is only escaped as XML text
+	var tmpl *template.Template
+	var pkgTmpl *textTemplate.Template
+	if tmpl, err = template.New("").Funcs(templateFuncs).ParseFS(epubFiles, "epub/*.xhtml"); err != nil {
+		return err
+	}
+	if pkgTmpl, err = textTemplate.ParseFS(epubFiles, "epub/content.opf"); err != nil {
+		return err
+	}
+
+	// Recipe names and the directory they are relative to
+	var srcDir string
+	var names []string
+	if info, _ := os.Stat(src); info.IsDir() {
+		srcDir = src
+		if names, err = collectRecipes(srcDir); err != nil {
+			return err
+		}
+	} else {
+		srcDir = filepath.Dir(src)
+		names = []string{strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))}
+	}
+	if len(names) == 0 {
+		return errors.New("No recipes found in: " + src)
+	}
+
+	if opts.Title == "" {
+		var absSrc string
+		if absSrc, err = filepath.Abs(src); err != nil {
+			return err
+		}
+		opts.Title = recipe.FilepathToName(absSrc)
+	}
+
+	// Pages live within `recipes/`, relative to the package document
+	pagePath := func(name string) string { return "recipes/" + name + ".xhtml" }
+	recipes := map[string]*cook.Recipe{}
+	known := map[string]bool{}
+	byHref := map[string]string{}
+	for _, name := range names {
+		p := filepath.Join(srcDir, filepath.FromSlash(name)+".cook")
+		if recipes[name], err = parseRecipeFile(p, opts.Render); err != nil {
+			return err
+		}
+		known["recipes/"+name] = true
+		byHref[pagePath(name)] = name
+	}
+
+	contents := tocEntries(names, pagePath)
+	names = tocOrder(contents, byHref)
+
+	zw := zip.NewWriter(w)
+
+	// The mimetype must come first, uncompressed
+	if err = writeZipFile(zw, "mimetype", []byte("application/epub+zip"), true); err != nil {
+		return err
+	}
+
+	var container []byte
+	if container, err = epubFiles.ReadFile("epub/container.xml"); err != nil {
+		return err
+	}
+	if err = writeZipFile(zw, "META-INF/container.xml", container, false); err != nil {
+		return err
+	}
+
+	var style []byte
+	if style, err = epubFiles.ReadFile("epub/style.css"); err != nil {
+		return err
+	}
+	if err = writeZipFile(zw, "OEBPS/style.css", style, false); err != nil {
+		return err
+	}
+
+	pkg := packagePage{
+		Title:    opts.Title,
+		Modified: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+		Items: []epubItem{
+			{ID: "nav", Href: "nav.xhtml", MediaType: "application/xhtml+xml", Properties: "nav"},
+			{ID: "style", Href: "style.css", MediaType: "text/css"},
+		},
+		Spine: []string{"nav"},
+	}
+
+	// One page per recipe, in reading order
+	for i, name := range names {
+		r := recipes[name]
+		page := EPUBRecipePage{
+			Title:  r.Name,
+			Recipe: r,
+			Steps:  stepViews("recipes/"+name, r, known, ".xhtml", opts.Render),
+			Style:  relLink("recipes/"+name, "style.css"),
+		}
+
+		imagePath := siblingImage(filepath.Join(srcDir, filepath.FromSlash(name)+".cook"))
+		if imagePath != "" {
+			var image []byte
+			if image, err = os.ReadFile(imagePath); err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(imagePath))
+			href := "recipes/" + name + ext
+			if err = writeZipFile(zw, "OEBPS/"+href, image, true); err != nil {
+				return err
+			}
+			pkg.Items = append(pkg.Items, epubItem{
+				ID:        fmt.Sprintf("image%d", i+1),
+				Href:      escapePath(href),
+				MediaType: imageMediaTypes[ext],
+			})
+			page.Image = path.Base(name) + ext
+		}
+
+		id := fmt.Sprintf("recipe%d", i+1)
+		if err = writeZipTemplate(zw, tmpl, "recipe.xhtml", "OEBPS/"+pagePath(name), page); err != nil {
+			return err
+		}
+		pkg.Items = append(pkg.Items, epubItem{
+			ID:        id,
+			Href:      escapePath(pagePath(name)),
+			MediaType: "application/xhtml+xml",
+		})
+		pkg.Spine = append(pkg.Spine, id)
+	}
+
+	index := IngredientIndexPage{Letters: ingredientIndex(names, recipes, pagePath)}
+	if err = writeZipTemplate(zw, tmpl, "ingredients.xhtml", "OEBPS/ingredients.xhtml", index); err != nil {
+		return err
+	}
+	pkg.Items = append(pkg.Items, epubItem{ID: "ingredients", Href: "ingredients.xhtml", MediaType: "application/xhtml+xml"})
+	pkg.Spine = append(pkg.Spine, "ingredients")
+
+	nav := NavPage{Title: opts.Title, Contents: contents}
+	if err = writeZipTemplate(zw, tmpl, "nav.xhtml", "OEBPS/nav.xhtml", nav); err != nil {
+		return err
+	}
+
+	pkg.Identifier = bookIdentifier(hashBytes([]byte(opts.Title + "\n" + strings.Join(names, "\n"))))
+	if err = writeZipTemplate(zw, pkgTmpl, "content.opf", "OEBPS/content.opf", pkg); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
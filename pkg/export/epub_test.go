@@ -0,0 +1,63 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestExportEPUB(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "toast.cook", "Toast @bread{2%slices} with @butter.")
+	writeTestFile(t, dir, "toast.jpg", "jpeg")
+	writeTestFile(t, dir, "sauces/hollandaise.cook", "Whisk @egg yolks{3} into @Butter{100%g}.")
+
+	var buf bytes.Buffer
+	if err := ExportEPUB(dir, &buf, EPUBOptions{Title: "Test"}); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	book, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Invalid zip: %v", err)
+	}
+
+	// The mimetype must come first, uncompressed and without an extra field
+	if first := book.File[0]; first.Name != "mimetype" || first.Method != zip.Store || len(first.Extra) != 0 {
+		t.Fatalf("Wrong first entry: %v (method %v, extra %v)", first.Name, first.Method, first.Extra)
+	}
+
+	files := map[string]string{}
+	for _, file := range book.File {
+		reader, _ := file.Open()
+		data, _ := io.ReadAll(reader)
+		reader.Close()
+		files[file.Name] = string(data)
+	}
+
+	for _, name := range []string{"META-INF/container.xml", "OEBPS/content.opf",
+		"OEBPS/nav.xhtml", "OEBPS/recipes/toast.xhtml", "OEBPS/recipes/toast.jpg",
+		"OEBPS/recipes/sauces/hollandaise.xhtml", "OEBPS/ingredients.xhtml"} {
+		if _, ok := files[name]; !ok {
+			t.Fatalf("Missing %v", name)
+		}
+	}
+	if !strings.Contains(files["OEBPS/content.opf"], `href="recipes/toast.jpg" media-type="image/jpeg"`) {
+		t.Fatalf("Photo missing from manifest\n%v", files["OEBPS/content.opf"])
+	}
+	if !strings.Contains(files["OEBPS/nav.xhtml"], "<span>sauces</span>") {
+		t.Fatalf("Folders missing from contents\n%v", files["OEBPS/nav.xhtml"])
+	}
+
+	// Ingredients are indexed case insensitively
+	index := files["OEBPS/ingredients.xhtml"]
+	if !strings.Contains(index, `butter: <a href="recipes/toast.xhtml">toast</a>, `+
+		`<a href="recipes/sauces/hollandaise.xhtml">hollandaise</a>`) {
+		t.Fatalf("Wrong ingredient index\n%v", index)
+	}
+}
@@ -0,0 +1,33 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Writes `text` into the file `name` (slash separated) within `dir`, creating
+// parent directories as needed.
+func writeTestFile(t *testing.T, dir string, name string, text string) {
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Fails unless the file at `p` contains each of `want`
+func testFileContains(t *testing.T, p string, want ...string) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range want {
+		if !strings.Contains(string(data), text) {
+			t.Fatalf("Missing from %v\ngot:\n%s\nwant: %q.", p, data, text)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package export
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+)
+
+// The built-in templates, these can be overridden by name with a user
+// template directory (see `config.ExportConfig`).
+//
+//go:embed templates/*.html
+var defaultTemplates embed.FS
+
+// Options for HTML exports
+type HTMLOptions struct {
+	// A directory of templates overriding the built-ins, ignored if empty
+	TemplateDir string
+	// Options passed on to chunk rendering (e.g. temperature units)
+	Render recipe.RenderOptions
+}
+
+// A single chunk of a step, prepared for a template
+type ChunkView struct {
+	Kind string // "text", "ingredient", "cookware", "timer" or "temperature"
+	Text string
+	Href string // Link to a referenced recipe, if any
+}
+
+// The data passed to the `recipe.html` template
+type RecipePage struct {
+	Title  string
+	Recipe *cook.Recipe
+	Steps  [][]ChunkView
+	Index  string // Relative link to the index page, empty for lone recipes
+	// The recipe as schema.org JSON-LD, for search engines
+	Schema recipe.SchemaRecipe
+}
+
+// A link to a recipe page
+type LinkView struct {
+	Name string
+	Href string
+}
+
+// A folder of recipes, `Name` is empty for the root folder
+type FolderView struct {
+	Name    string
+	Recipes []LinkView
+}
+
+// The data passed to the `index.html` template
+type IndexPage struct {
+	Title   string
+	Folders []FolderView
+}
+
+// Functions available within templates
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	// Formats the quantity and unit of an ingredient or cookware
+	"amount": func(c any) string {
+		switch c := c.(type) {
+		case cook.Ingredient:
+			return recipe.FormatAmount(cook.Component(c))
+		case cook.Cookware:
+			return recipe.FormatAmount(cook.Component(c))
+		case cook.Timer:
+			return recipe.FormatAmount(cook.Component(c))
+		default:
+			return ""
+		}
+	},
+}
+
+// Parses the built-in templates, then any `*.html` templates in `dir` (which
+// replace built-ins of the same name).
+func LoadHTMLTemplates(dir string) (*template.Template, error) {
+	var err error
+	tmpl := template.New("").Funcs(templateFuncs)
+
+	if tmpl, err = tmpl.ParseFS(defaultTemplates, "templates/*.html"); err != nil {
+		return nil, err
+	}
+
+	if dir == "" {
+		return tmpl, nil
+	}
+
+	var matches []string
+	if matches, err = filepath.Glob(filepath.Join(dir, "*.html")); err != nil {
+		return nil, err
+	}
+	if len(matches) > 0 {
+		if tmpl, err = tmpl.ParseFiles(matches...); err != nil {
+			return nil, err
+		}
+	}
+	return tmpl, nil
+}
+
+// Converts a relative OS path to a URL path, e.g. `sauces\hollandaise` -> `sauces/hollandaise`
+func toURL(relPath string) string {
+	return filepath.ToSlash(relPath)
+}
+
+// Builds the chunk views of each step of `r`, located at `name` (relative
+// to the export root).
+//
+// Recipe references link to their page (with extension `ext`) if the
+// referenced recipe is in `known`.
+func stepViews(name string, r *cook.Recipe, known map[string]bool, ext string, opts recipe.RenderOptions) [][]ChunkView {
+	steps := make([][]ChunkView, len(r.Steps))
+	for i, step := range r.Steps {
+		views := make([]ChunkView, 0, len(step))
+		for _, chunk := range step {
+			view := ChunkView{Text: recipe.ChunkText(chunk, opts)}
+			switch chunk := chunk.(type) {
+			case cook.Text:
+				view.Kind = "text"
+			case cook.Ingredient:
+				view.Kind = "ingredient"
+				if chunk.IsRecipeRef() {
+					ref := path.Clean(path.Join(path.Dir(toURL(name)), chunk.Name))
+					if known[ref] {
+						view.Href = relLink(name, ref+ext)
+					}
+				}
+			case cook.Cookware:
+				view.Kind = "cookware"
+			case cook.Timer:
+				view.Kind = "timer"
+			case cook.Temperature:
+				view.Kind = "temperature"
+			}
+			views = append(views, view)
+		}
+		steps[i] = views
+	}
+	return steps
+}
+
+// Returns a relative link from the page of `from` to `target`, both relative
+// to the export root.
+//
+// e.g. ("mains/pasta", "sauces/pesto.html") -> "../sauces/pesto.html"
+func relLink(from string, target string) string {
+	rel, err := filepath.Rel(
+		filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(target))
+	if err != nil {
+		// Both are relative to the root, so this shouldn't occur
+		return toURL(target)
+	}
+	return toURL(rel)
+}
+
+// Collects every `.cook` file under `dir`, returned as slash-separated paths
+// relative to `dir` (without extension) in sorted order.
+func collectRecipes(dir string) ([]string, error) {
+	names := make([]string, 0)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".cook" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		names = append(names, strings.TrimSuffix(toURL(rel), ".cook"))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// Reads and parses a recipe file, with temperatures post-processed if
+// `opts.Temperatures` is set.
+func parseRecipeFile(p string, opts recipe.RenderOptions) (*cook.Recipe, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	r := cook.ParseRecipe(recipe.FilepathToName(p), &data)
+	if opts.Temperatures {
+		cook.ParseTemperatures(&r)
+	}
+	return &r, nil
+}
+
+// Writes `tmplName` executed with `data` into the file at `outPath`, creating
+// parent directories as needed.
+func writeTemplate(tmpl *template.Template, tmplName string, outPath string, data any) error {
+	var err error
+	if err = os.MkdirAll(filepath.Dir(outPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	var file *os.File
+	if file, err = os.Create(outPath); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return tmpl.ExecuteTemplate(file, tmplName, data)
+}
+
+// Renders a single recipe page using the `recipe.html` template.
+func RenderHTML(w io.Writer, r *cook.Recipe, opts HTMLOptions) error {
+	tmpl, err := LoadHTMLTemplates(opts.TemplateDir)
+	if err != nil {
+		return err
+	}
+
+	page := RecipePage{
+		Title:  r.Name,
+		Recipe: r,
+		Steps:  stepViews(r.Name, r, map[string]bool{}, ".html", opts.Render),
+		Schema: recipe.ToSchemaRecipe(r, opts.Render),
+	}
+	return tmpl.ExecuteTemplate(w, "recipe.html", page)
+}
+
+// Exports a single `.cook` file as a standalone HTML page at `outPath`.
+func ExportHTMLRecipe(src string, outPath string, opts HTMLOptions) error {
+	var err error
+	var r *cook.Recipe
+
+	if r, err = parseRecipeFile(src, opts.Render); err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(outPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	var file *os.File
+	if file, err = os.Create(outPath); err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return RenderHTML(file, r, opts)
+}
+
+// Exports every recipe within `srcDir` (recursively) into `outDir`, with one
+// page per recipe (mirroring the folder structure) and an `index.html`
+// listing every recipe by folder.
+//
+// Recipe references link between pages using relative links, so the output
+// can be opened directly from disk.
+func ExportHTMLDir(srcDir string, outDir string, opts HTMLOptions) error {
+	var err error
+
+	if !common.FileExists(srcDir) {
+		return errors.New("Directory not found: " + srcDir)
+	}
+
+	var tmpl *template.Template
+	if tmpl, err = LoadHTMLTemplates(opts.TemplateDir); err != nil {
+		return err
+	}
+
+	var names []string
+	if names, err = collectRecipes(srcDir); err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, name := range names {
+		known[name] = true
+	}
+
+	// One page per recipe
+	for _, name := range names {
+		var r *cook.Recipe
+		if r, err = parseRecipeFile(filepath.Join(srcDir, filepath.FromSlash(name)+".cook"), opts.Render); err != nil {
+			return err
+		}
+
+		page := RecipePage{
+			Title:  r.Name,
+			Recipe: r,
+			Steps:  stepViews(name, r, known, ".html", opts.Render),
+			Index:  relLink(name, "index.html"),
+			Schema: recipe.ToSchemaRecipe(r, opts.Render),
+		}
+		outPath := filepath.Join(outDir, filepath.FromSlash(name)+".html")
+		if err = writeTemplate(tmpl, "recipe.html", outPath, page); err != nil {
+			return fmt.Errorf("Failed to export %v: %w", name, err)
+		}
+	}
+
+	// The index, grouped by folder
+	var absDir string
+	if absDir, err = filepath.Abs(srcDir); err != nil {
+		return err
+	}
+	index := IndexPage{
+		Title:   recipe.FilepathToName(absDir),
+		Folders: folderViews(names, ""),
+	}
+	return writeTemplate(tmpl, "index.html", filepath.Join(outDir, "index.html"), index)
+}
+
+// Groups recipe names by their folder, with links relative to the page of
+// `from` (use "" for the export root).
+func folderViews(names []string, from string) []FolderView {
+	folders := make([]FolderView, 0)
+	byFolder := map[string]int{}
+	for _, name := range names {
+		folder := path.Dir(name)
+		if folder == "." {
+			folder = ""
+		}
+
+		i, ok := byFolder[folder]
+		if !ok {
+			i = len(folders)
+			byFolder[folder] = i
+			folders = append(folders, FolderView{Name: folder, Recipes: []LinkView{}})
+		}
+
+		folders[i].Recipes = append(folders[i].Recipes, LinkView{
+			Name: recipe.FilepathToName(name),
+			Href: relLink(from, name+".html"),
+		})
+	}
+
+	sort.SliceStable(folders, func(i, j int) bool {
+		return folders[i].Name < folders[j].Name
+	})
+	return folders
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestRenderHTML(t *testing.T) {
+	r := cook.ParseRecipeString("fish & <chips>",
+		">> servings: 2\nFry @fish{2%fillets} in a #pan{} at 180°C for ~{4%minutes}.")
+	cook.ParseTemperatures(&r)
+
+	var sb strings.Builder
+	if err := RenderHTML(&sb, &r, HTMLOptions{Render: recipe.RenderOptions{Units: units.Metric}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, text := range []string{
+		`<title>fish &amp; &lt;chips&gt;</title>`,
+		`<li><strong>servings:</strong> 2</li>`,
+		`<li>2 fillets fish</li>`,
+		`<span class="ingredient">fish</span>`,
+		`<span class="cookware">pan</span>`,
+		`<span class="temperature">180°C</span>`,
+		`<span class="timer">4 minutes</span>`,
+		`"@type":"Recipe"`,
+	} {
+		if !strings.Contains(sb.String(), text) {
+			t.Fatalf("Missing from page\ngot:\n%v\nwant: %q.", sb.String(), text)
+		}
+	}
+	if strings.Contains(sb.String(), "<nav>") {
+		t.Fatalf("A lone recipe links to an index\ngot:\n%v.", sb.String())
+	}
+}
+
+func TestExportHTMLDir(t *testing.T) {
+	src := t.TempDir()
+	writeTestFile(t, src, "toast.cook", "Toast @bread{2%slices}.")
+	writeTestFile(t, src, "mains/eggs.cook", "Top @eggs{2} with @../sauces/hollandaise{} and @./missing{}.")
+	writeTestFile(t, src, "sauces/hollandaise.cook", "Whisk @egg yolks{3}.")
+	writeTestFile(t, src, "sauces/notes.txt", "Not a recipe")
+
+	// Override the recipe page, keeping the built-in index
+	templates := t.TempDir()
+	custom := `{{define "recipe.html"}}<h1>{{.Recipe.Name}}</h1><a href="{{.Index}}">index</a>` +
+		`{{range .Steps}}{{range .}}{{if .Href}}<a href="{{.Href}}">{{.Text}}</a>{{else}}{{.Text}}{{end}}{{end}}{{end}}{{end}}`
+	writeTestFile(t, templates, "recipe.html", custom)
+
+	out := filepath.Join(t.TempDir(), "site")
+	if err := ExportHTMLDir(src, out, HTMLOptions{TemplateDir: templates}); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+
+	testFileContains(t, filepath.Join(out, "index.html"),
+		`<a href="toast.html">toast</a>`,
+		`<h2>mains</h2>`,
+		`<a href="mains/eggs.html">eggs</a>`,
+		`<a href="sauces/hollandaise.html">hollandaise</a>`)
+	testFileContains(t, filepath.Join(out, "mains", "eggs.html"),
+		`<h1>eggs</h1>`,
+		`<a href="../index.html">index</a>`,
+		`<a href="../sauces/hollandaise.html">../sauces/hollandaise</a>`,
+		` and ./missing.`)
+	testFileContains(t, filepath.Join(out, "toast.html"), `<a href="index.html">index</a>`)
+	if _, err := os.Stat(filepath.Join(out, "sauces", "notes.html")); err == nil {
+		t.Fatalf("Exported a file which isn't a recipe")
+	}
+
+	if err := ExportHTMLDir(filepath.Join(src, "nothing"), out, HTMLOptions{}); err == nil {
+		t.Fatalf("Exported a missing directory")
+	}
+}
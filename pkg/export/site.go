@@ -0,0 +1,462 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/api"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/recipe"
+)
+
+// The file (within the output directory) recording what the last site build
+// rendered, used to skip unchanged recipes.
+const siteManifestFile = ".cook-site.json"
+
+// The file (within the output directory) holding the client-side search index
+const siteSearchFile = "search.json"
+
+// Options for site builds
+type SiteOptions struct {
+	HTMLOptions
+	// Re-render every page, even if its recipe is unchanged
+	Force bool
+}
+
+// Counts of the recipe pages handled by a site build
+type SiteStats struct {
+	Rendered int
+	Skipped  int
+	Removed  int
+}
+
+// The data passed to the `folder.html` template
+type FolderPage struct {
+	Title   string
+	Parent  string // Relative link to the parent folder, empty for the root
+	Folders []LinkView
+	Recipes []LinkView
+	Tags    string // Relative link to the tag index
+	Search  string // Relative link to the search page
+}
+
+// A tag and the recipes which use it
+type TagView struct {
+	Name    string
+	Recipes []LinkView
+}
+
+// The data passed to the `tags.html` template
+type TagsPage struct {
+	Title string
+	Tags  []TagView
+	Index string
+}
+
+// The data passed to the `search.html` template
+type SearchPage struct {
+	Title     string
+	Index     string
+	IndexJSON string // Relative link to the search index
+}
+
+// A single recipe within the search index
+type SearchEntry struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	Tags        []string `json:"tags"`
+	Ingredients []string `json:"ingredients"`
+}
+
+// What was rendered for a single recipe, kept between builds
+type siteEntry struct {
+	Hash        string   `json:"hash"`
+	Title       string   `json:"title"`
+	Tags        []string `json:"tags"`
+	Ingredients []string `json:"ingredients"`
+	// Whether the recipe references other recipes, these pages are
+	// re-rendered when recipes are added or removed (to update links)
+	HasRefs bool `json:"hasRefs"`
+}
+
+// The record of a site build
+type siteManifest struct {
+	// A hash of the templates and options used, a change invalidates every page
+	Options string               `json:"options"`
+	Recipes map[string]siteEntry `json:"recipes"`
+	// Folders with a listing page, relative to the site root ("" is the root)
+	Folders []string `json:"folders"`
+}
+
+// Returns the hex encoded SHA-256 of `data`
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Hashes everything, other than the recipes themselves, which affects the
+// rendered pages.
+func hashSiteOptions(opts SiteOptions) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(opts.Render.Units))
+	hash.Write([]byte(strconv.FormatBool(opts.Render.Temperatures)))
+
+	// The built-ins change between versions
+	builtins, err := fs.Glob(defaultTemplates, "templates/*.html")
+	if err != nil {
+		return "", err
+	}
+	for _, builtin := range builtins {
+		var data []byte
+		if data, err = defaultTemplates.ReadFile(builtin); err != nil {
+			return "", err
+		}
+		hash.Write(data)
+	}
+
+	if opts.TemplateDir == "" {
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(opts.TemplateDir, "*.html"))
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(matches)
+	for _, match := range matches {
+		var data []byte
+		if data, err = os.ReadFile(match); err != nil {
+			return "", err
+		}
+		hash.Write([]byte(filepath.Base(match)))
+		hash.Write(data)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Reads the manifest of a previous build in `outDir`. A missing or unreadable
+// manifest is treated as an empty one.
+func loadSiteManifest(outDir string) siteManifest {
+	manifest := siteManifest{Recipes: map[string]siteEntry{}}
+	data, err := os.ReadFile(filepath.Join(outDir, siteManifestFile))
+	if err != nil {
+		return manifest
+	}
+	if err = json.Unmarshal(data, &manifest); err != nil || manifest.Recipes == nil {
+		return siteManifest{Recipes: map[string]siteEntry{}}
+	}
+	return manifest
+}
+
+// Writes `data` as JSON into the file at `outPath`
+func writeJSON(outPath string, data any) error {
+	jsonBytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outPath, jsonBytes, 0644)
+}
+
+// Returns the folder of a recipe name, "" for the root
+func folderOf(name string) string {
+	folder := path.Dir(name)
+	if folder == "." {
+		return ""
+	}
+	return folder
+}
+
+// Returns the path of a folder's listing page, relative to the site root
+func folderIndex(folder string) string {
+	return path.Join(folder, "index.html")
+}
+
+// Returns every folder holding a recipe (directly or nested), including the
+// root "", sorted.
+func recipeFolders(names []string) []string {
+	seen := map[string]bool{"": true}
+	folders := []string{""}
+	for _, name := range names {
+		for folder := folderOf(name); folder != "" && !seen[folder]; folder = folderOf(folder) {
+			seen[folder] = true
+			folders = append(folders, folder)
+		}
+	}
+	sort.Strings(folders)
+	return folders
+}
+
+// Builds a static site of the whole recipe library into `outDir`.
+//
+// The site holds a page per recipe, a listing per folder, a tag index (from
+// the `tag`/`tags` metadata of each recipe), a search page and the JSON index
+// it searches (`search.json`). All links are relative, so the output can be
+// hosted anywhere.
+//
+// Rebuilds only re-render recipes whose source changed since the last build,
+// unless `opts.Force` is set or the templates/options changed. Pages of
+// recipes which no longer exist are removed, as are listings of folders left
+// without recipes.
+func BuildSite(outDir string, opts SiteOptions) (SiteStats, error) {
+	var err error
+	stats := SiteStats{}
+
+	var tmpl *template.Template
+	if tmpl, err = LoadHTMLTemplates(opts.TemplateDir); err != nil {
+		return stats, err
+	}
+	if err = os.MkdirAll(outDir, os.ModePerm); err != nil {
+		return stats, err
+	}
+
+	var osNames []string
+	if osNames, err = api.GetAllRecipeNames(); err != nil {
+		return stats, err
+	}
+	names := make([]string, 0, len(osNames))
+	known := map[string]bool{}
+	for _, name := range osNames {
+		name = toURL(name)
+		names = append(names, name)
+		known[name] = true
+	}
+	sort.Strings(names)
+
+	var optionsHash string
+	if optionsHash, err = hashSiteOptions(opts); err != nil {
+		return stats, err
+	}
+
+	// Pages are only reused if they were rendered the same way
+	old := loadSiteManifest(outDir)
+	reuse := !opts.Force && old.Options == optionsHash
+
+	// Links to references change when recipes are added or removed
+	recipesChanged := len(old.Recipes) != len(names)
+	for name := range old.Recipes {
+		if !known[name] {
+			recipesChanged = true
+		}
+	}
+
+	manifest := siteManifest{
+		Options: optionsHash,
+		Recipes: map[string]siteEntry{},
+		Folders: recipeFolders(names),
+	}
+
+	// Recipe pages
+	for _, name := range names {
+		var src []byte
+		if src, err = api.GetRecipeSource(filepath.FromSlash(name)); err != nil {
+			return stats, err
+		}
+
+		hash := hashBytes(src)
+		outPath := filepath.Join(outDir, filepath.FromSlash(name)+".html")
+		prev, ok := old.Recipes[name]
+		if reuse && ok && prev.Hash == hash && !(recipesChanged && prev.HasRefs) {
+			if _, err = os.Stat(outPath); err == nil {
+				manifest.Recipes[name] = prev
+				stats.Skipped++
+				continue
+			}
+		}
+
+		r := cook.ParseRecipe(recipe.FilepathToName(name), &src)
+		if opts.Render.Temperatures {
+			cook.ParseTemperatures(&r)
+		}
+
+		page := RecipePage{
+			Title:  r.Name,
+			Recipe: &r,
+			Steps:  stepViews(name, &r, known, ".html", opts.Render),
+			Index:  relLink(name, folderIndex(folderOf(name))),
+			Schema: recipe.ToSchemaRecipe(&r, opts.Render),
+		}
+		if err = writeTemplate(tmpl, "recipe.html", outPath, page); err != nil {
+			return stats, fmt.Errorf("Failed to render %v: %w", name, err)
+		}
+
+		entry := siteEntry{
+			Hash:        hash,
+			Title:       r.Name,
+			Tags:        recipe.Tags(&r),
+			Ingredients: make([]string, 0, len(r.Ingredients)),
+		}
+		seen := map[string]bool{}
+		for _, ingr := range r.Ingredients {
+			if ingr.IsRecipeRef() {
+				entry.HasRefs = true
+				continue
+			}
+			if key := strings.ToLower(ingr.Name); !seen[key] {
+				seen[key] = true
+				entry.Ingredients = append(entry.Ingredients, ingr.Name)
+			}
+		}
+		manifest.Recipes[name] = entry
+		stats.Rendered++
+	}
+
+	// Pages of removed recipes
+	for name := range old.Recipes {
+		if known[name] {
+			continue
+		}
+		outPath := filepath.Join(outDir, filepath.FromSlash(name)+".html")
+		if err = os.Remove(outPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return stats, err
+		}
+		stats.Removed++
+	}
+
+	// Listings, the tag index and search are cheap to build from the manifest,
+	// so they are always rebuilt
+	if err = writeFolderPages(tmpl, outDir, names, manifest.Folders); err != nil {
+		return stats, err
+	}
+	if err = removeFolderPages(outDir, old.Folders, manifest.Folders); err != nil {
+		return stats, err
+	}
+	if err = writeTagsPage(tmpl, outDir, names, manifest); err != nil {
+		return stats, err
+	}
+	if err = writeSearch(tmpl, outDir, names, manifest); err != nil {
+		return stats, err
+	}
+
+	return stats, writeJSON(filepath.Join(outDir, siteManifestFile), manifest)
+}
+
+// Writes the listing page of each of `folders`, see `recipeFolders`.
+func writeFolderPages(tmpl *template.Template, outDir string, names []string, folders []string) error {
+	for _, folder := range folders {
+		index := folderIndex(folder)
+		title := recipe.FilepathToName(folder)
+		if folder == "" {
+			title = "Recipes"
+		}
+
+		page := FolderPage{
+			Title:   title,
+			Folders: []LinkView{},
+			Recipes: []LinkView{},
+			Tags:    relLink(index, "tags.html"),
+			Search:  relLink(index, "search.html"),
+		}
+		if folder != "" {
+			page.Parent = relLink(index, folderIndex(folderOf(folder)))
+		}
+
+		for _, sub := range folders {
+			if sub != "" && sub != folder && folderOf(sub) == folder {
+				page.Folders = append(page.Folders, LinkView{
+					Name: path.Base(sub),
+					Href: relLink(index, folderIndex(sub)),
+				})
+			}
+		}
+
+		for _, name := range names {
+			if folderOf(name) == folder {
+				page.Recipes = append(page.Recipes, LinkView{
+					Name: recipe.FilepathToName(name),
+					Href: relLink(index, name+".html"),
+				})
+			}
+		}
+
+		outPath := filepath.Join(outDir, filepath.FromSlash(index))
+		if err := writeTemplate(tmpl, "folder.html", outPath, page); err != nil {
+			return fmt.Errorf("Failed to render folder %q: %w", folder, err)
+		}
+	}
+	return nil
+}
+
+// Removes the listing pages of folders in `old` which aren't in `current`,
+// along with the folders themselves once empty.
+func removeFolderPages(outDir string, old []string, current []string) error {
+	keep := map[string]bool{}
+	for _, folder := range current {
+		keep[folder] = true
+	}
+
+	// Deepest first, so parents are empty by the time they're reached
+	stale := make([]string, 0)
+	for _, folder := range old {
+		if !keep[folder] {
+			stale = append(stale, folder)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(stale)))
+
+	for _, folder := range stale {
+		index := filepath.Join(outDir, filepath.FromSlash(folderIndex(folder)))
+		if err := os.Remove(index); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		// Anything else left in the folder (e.g. user files) keeps it around
+		os.Remove(filepath.Dir(index))
+	}
+	return nil
+}
+
+// Writes the tag index, listing the recipes of every tag
+func writeTagsPage(tmpl *template.Template, outDir string, names []string, manifest siteManifest) error {
+	byTag := map[string][]LinkView{}
+	for _, name := range names {
+		for _, tag := range manifest.Recipes[name].Tags {
+			byTag[tag] = append(byTag[tag], LinkView{
+				Name: recipe.FilepathToName(name),
+				Href: name + ".html",
+			})
+		}
+	}
+
+	page := TagsPage{Title: "Tags", Tags: []TagView{}, Index: "index.html"}
+	for tag, links := range byTag {
+		page.Tags = append(page.Tags, TagView{Name: tag, Recipes: links})
+	}
+	sort.Slice(page.Tags, func(i, j int) bool {
+		return page.Tags[i].Name < page.Tags[j].Name
+	})
+
+	return writeTemplate(tmpl, "tags.html", filepath.Join(outDir, "tags.html"), page)
+}
+
+// Writes the search index and the page which searches it
+func writeSearch(tmpl *template.Template, outDir string, names []string, manifest siteManifest) error {
+	entries := make([]SearchEntry, 0, len(names))
+	for _, name := range names {
+		entry := manifest.Recipes[name]
+		entries = append(entries, SearchEntry{
+			Name:        recipe.FilepathToName(name),
+			Title:       entry.Title,
+			URL:         name + ".html",
+			Tags:        entry.Tags,
+			Ingredients: entry.Ingredients,
+		})
+	}
+
+	if err := writeJSON(filepath.Join(outDir, siteSearchFile), entries); err != nil {
+		return err
+	}
+
+	page := SearchPage{Title: "Search", Index: "index.html", IndexJSON: siteSearchFile}
+	return writeTemplate(tmpl, "search.html", filepath.Join(outDir, "search.html"), page)
+}
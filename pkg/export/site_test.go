@@ -0,0 +1,126 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/config"
+	"github.com/BurntSushi/toml"
+)
+
+// Loads a config with a temporary recipes directory, returning it
+func loadSiteConfig(t *testing.T) string {
+	dir := t.TempDir()
+	conf := config.Config{
+		Recipe:   config.RecipeConfig{Dir: filepath.Join(dir, "recipes")},
+		Shopping: config.ShoppingConfig{Dir: filepath.Join(dir, "shopping")},
+	}
+	if err := os.MkdirAll(conf.Recipe.Dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(dir, "config.toml")
+	file, err := os.Create(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err = toml.NewEncoder(file).Encode(conf); err != nil {
+		t.Fatal(err)
+	}
+	if !config.LoadConfig(configPath) {
+		t.Fatal("Failed to load test config")
+	}
+	return conf.Recipe.Dir
+}
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestBuildSite(t *testing.T) {
+	recipes := loadSiteConfig(t)
+	writeTestFile(t, recipes, "toast.cook", ">> tags: quick\nToast @bread{2%slices}.")
+	writeTestFile(t, recipes, "mains/eggs.cook", ">> tags: quick, brunch\nTop @eggs{2} with @../sauces/hollandaise{}.")
+	writeTestFile(t, recipes, "sauces/hollandaise.cook", "Whisk @egg yolks{3} into @butter{100%g}.")
+	writeTestFile(t, recipes, "sides/fried/chips.cook", "Fry @potatoes{4}.")
+
+	out := filepath.Join(t.TempDir(), "site")
+	testBuild := func(opts SiteOptions, want SiteStats) {
+		stats, err := BuildSite(out, opts)
+		if err != nil {
+			t.Fatalf("Failed to build: %v", err)
+		}
+		if stats != want {
+			t.Fatalf("Wrong build stats\ngot: %+v\nwant: %+v.", stats, want)
+		}
+	}
+	testExists := func(name string, want bool) {
+		_, err := os.Stat(filepath.Join(out, filepath.FromSlash(name)))
+		if exists := err == nil; exists != want {
+			t.Fatalf("Wrong existence of %v\ngot: %v\nwant: %v.", name, exists, want)
+		}
+	}
+
+	// A fresh build renders everything
+	testBuild(SiteOptions{}, SiteStats{Rendered: 4})
+	for _, name := range []string{"index.html", "toast.html", "mains/index.html",
+		"mains/eggs.html", "sides/index.html", "sides/fried/index.html",
+		"sides/fried/chips.html", "tags.html", "search.html", siteSearchFile, siteManifestFile} {
+		testExists(name, true)
+	}
+	testFileContains(t, filepath.Join(out, "mains", "eggs.html"),
+		`<a href="../sauces/hollandaise.html">`,
+		`<a href="index.html">&larr; All recipes</a>`)
+	testFileContains(t, filepath.Join(out, "index.html"),
+		`<a href="mains/index.html">mains</a>`,
+		`<a href="sides/index.html">sides</a>`,
+		`<a href="toast.html">toast</a>`)
+	testFileContains(t, filepath.Join(out, "sides", "index.html"),
+		`<a href="../index.html">&larr; Up</a>`,
+		`<a href="fried/index.html">fried</a>`)
+	testFileContains(t, filepath.Join(out, "tags.html"),
+		`<a href="mains/eggs.html">eggs</a>`, `brunch`)
+	testFileContains(t, filepath.Join(out, siteSearchFile),
+		`"url": "sauces/hollandaise.html"`, `"egg yolks"`)
+
+	// Unchanged recipes are skipped
+	testBuild(SiteOptions{}, SiteStats{Skipped: 4})
+
+	// Only the changed recipe is rendered, recorded by its new hash
+	writeTestFile(t, recipes, "toast.cook", ">> tags: quick\nToast @bread{4%slices}.")
+	testBuild(SiteOptions{}, SiteStats{Rendered: 1, Skipped: 3})
+	manifest := loadSiteManifest(out)
+	if got, want := manifest.Recipes["toast"].Hash,
+		hashBytes([]byte(">> tags: quick\nToast @bread{4%slices}.")); got != want {
+		t.Fatalf("Wrong manifest hash\ngot: %v\nwant: %v.", got, want)
+	}
+	if !manifest.Recipes["mains/eggs"].HasRefs || manifest.Recipes["toast"].HasRefs {
+		t.Fatalf("Wrong references in manifest\ngot: %+v.", manifest.Recipes)
+	}
+
+	// A removed recipe takes its page and emptied folders with it, and
+	// recipes with references are re-rendered to update their links
+	if err := os.RemoveAll(filepath.Join(recipes, "sides")); err != nil {
+		t.Fatal(err)
+	}
+	testBuild(SiteOptions{}, SiteStats{Rendered: 1, Skipped: 2, Removed: 1})
+	for _, name := range []string{"sides/fried/chips.html", "sides/fried/index.html",
+		"sides/index.html", "sides"} {
+		testExists(name, false)
+	}
+	if data, _ := os.ReadFile(filepath.Join(out, "index.html")); strings.Contains(string(data), "sides") {
+		t.Fatalf("Removed folder is still listed\ngot:\n%s.", data)
+	}
+
+	// Forcing, or changing the templates, renders everything again
+	testBuild(SiteOptions{Force: true}, SiteStats{Rendered: 3})
+	templates := t.TempDir()
+	custom := `{{define "recipe.html"}}<h1>{{.Recipe.Name}}</h1>{{end}}`
+	writeTestFile(t, templates, "recipe.html", custom)
+	testBuild(SiteOptions{HTMLOptions: HTMLOptions{TemplateDir: templates}}, SiteStats{Rendered: 3})
+	testFileContains(t, filepath.Join(out, "toast.html"), `<h1>toast</h1>`)
+	testBuild(SiteOptions{HTMLOptions: HTMLOptions{TemplateDir: templates}}, SiteStats{Skipped: 3})
+}
@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Cookware commonly named in recipes, annotated with `#`
+var commonCookware = []string{
+	"baking dish", "baking sheet", "baking tray", "blender", "bowl", "cake tin",
+	"casserole dish", "colander", "cutting board", "dutch oven", "food processor",
+	"frying pan", "grater", "griddle", "grill", "kettle", "knife", "ladle", "loaf tin",
+	"microwave", "mixing bowl", "muffin tin", "oven", "pan", "pie dish", "pot",
+	"pressure cooker", "roasting pan", "roasting tin", "rolling pin", "saucepan",
+	"sheet pan", "sieve", "skillet", "slow cooker", "spatula", "stand mixer",
+	"stockpot", "tray", "whisk", "wok",
+}
+
+// Matches written durations, e.g. "25 minutes" or "1-2 hrs". An escaped
+// tilde (approximately) before one is taken as part of it, e.g. "~5 min".
+var durationRegex = regexp.MustCompile(`(?i)(?:～\s*)?\b(\d+(?:[.,]\d+)?)(?:\s*(?:-|–|to)\s*(\d+(?:[.,]\d+)?))?\s*` +
+	`(seconds?|secs?|minutes?|mins?|hours?|hrs?)\b`)
+
+// Annotates steps in stages. Each annotation is swapped for a placeholder
+// until the end, so later stages can't match within earlier annotations.
+type annotator struct {
+	steps        []string
+	placeholders []string
+}
+
+// Replaces `steps[s][start:end]` with `text`, protected by a placeholder
+func (a *annotator) replace(s int, start int, end int, text string) {
+	a.placeholders = append(a.placeholders, text)
+	holder := fmt.Sprintf("\x00%d\x00", len(a.placeholders)-1)
+	a.steps[s] = a.steps[s][:start] + holder + a.steps[s][end:]
+}
+
+// Annotates the first mention matched by `regex` with the result of `format`,
+// returning whether there was a mention.
+func (a *annotator) first(regex *regexp.Regexp, format func(mention string) string) bool {
+	for s, step := range a.steps {
+		if loc := regex.FindStringIndex(step); loc != nil {
+			a.replace(s, loc[0], loc[1], format(step[loc[0]:loc[1]]))
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the steps with every annotation swapped in
+func (a *annotator) result() []string {
+	out := make([]string, len(a.steps))
+	for s, step := range a.steps {
+		for i, text := range a.placeholders {
+			step = strings.Replace(step, fmt.Sprintf("\x00%d\x00", i), text, 1)
+		}
+		out[s] = step
+	}
+	return out
+}
+
+// Formats an ingredient as a cooklang ingredient, with `text` as its name.
+//
+// e.g. `@plain flour{1.5%cups}`
+func formatIngredient(text string, ingr Ingredient) string {
+	amount := ingr.Qty
+	if ingr.Unit != "" {
+		amount += "%" + ingr.Unit
+	}
+	return "@" + text + "{" + amount + "}"
+}
+
+// Returns the regex matching mentions of `name` within text: whole words,
+// case insensitive and either singular or plural.
+func mentionRegex(name string) *regexp.Regexp {
+	singular := name
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, "oes"), strings.HasSuffix(lower, "ches"),
+		strings.HasSuffix(lower, "shes"), strings.HasSuffix(lower, "xes"):
+		singular = name[:len(name)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss"):
+		singular = name[:len(name)-1]
+	}
+	return regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(singular) + `(?:e?s)?\b`)
+}
+
+// Annotates the first mention of each ingredient. Ingredients are matched by
+// their full name, then by their last or first word (e.g. "butter" or "feta"
+// for "unsalted butter" and "feta cheese"), in which case the mention is
+// replaced by the full name.
+//
+// Returns the ingredients which weren't mentioned.
+func (a *annotator) ingredients(ingredients []Ingredient) []Ingredient {
+	// Longer names first, so "brown sugar" is found before "sugar"
+	order := make([]int, len(ingredients))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(x, y int) bool {
+		return len(ingredients[order[x]].Name) > len(ingredients[order[y]].Name)
+	})
+
+	found := make([]bool, len(ingredients))
+	for _, i := range order {
+		if ingredients[i].Name == "" {
+			continue
+		}
+		// Full matches keep their wording (e.g. plurals)
+		found[i] = a.first(mentionRegex(ingredients[i].Name), func(mention string) string {
+			return formatIngredient(mention, ingredients[i])
+		})
+	}
+	for _, i := range order {
+		words := strings.Fields(ingredients[i].Name)
+		if found[i] || len(words) < 2 {
+			continue
+		}
+		for _, word := range []string{words[len(words)-1], words[0]} {
+			partial := func(string) string { return formatIngredient(ingredients[i].Name, ingredients[i]) }
+			if len(word) > 2 && a.first(mentionRegex(word), partial) {
+				found[i] = true
+				break
+			}
+		}
+	}
+
+	missing := make([]Ingredient, 0)
+	for i, ingr := range ingredients {
+		if !found[i] && ingr.Name != "" {
+			missing = append(missing, ingr)
+		}
+	}
+	return missing
+}
+
+// Annotates the first mention of each common piece of cookware
+func (a *annotator) cookware() {
+	// Longer names first, so "frying pan" is found before "pan"
+	names := append([]string{}, commonCookware...)
+	sort.SliceStable(names, func(x, y int) bool {
+		return len(names[x]) > len(names[y])
+	})
+
+	for _, name := range names {
+		regex := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(name) + `\b`)
+		a.first(regex, func(mention string) string {
+			return "#" + mention + "{}"
+		})
+	}
+}
+
+// Annotates every written duration as a timer. Ranges use their upper bound,
+// e.g. "20-25 minutes" -> `~{25%minutes}`
+func (a *annotator) timers() {
+	for s := range a.steps {
+		for {
+			match := durationRegex.FindStringSubmatchIndex(a.steps[s])
+			if match == nil {
+				break
+			}
+			step := a.steps[s]
+			qty := step[match[2]:match[3]]
+			if match[4] >= 0 {
+				qty = step[match[4]:match[5]]
+			}
+			qty = strings.Replace(qty, ",", ".", 1)
+			unit := step[match[6]:match[7]]
+			a.replace(s, match[0], match[1], "~{"+qty+"%"+unit+"}")
+		}
+	}
+}
+
+// Annotates plain text steps as cooklang: the first mention of each ingredient
+// with `@`, common cookware with `#` and durations with `~`. Cooklang syntax
+// already within the text is neutralized first (see `escapeText`).
+//
+// Returns the annotated steps and the ingredients which weren't mentioned.
+func Annotate(steps []string, ingredients []Ingredient) ([]string, []Ingredient) {
+	a := annotator{steps: make([]string, 0, len(steps))}
+	for _, step := range steps {
+		a.steps = append(a.steps, escapeText(step))
+	}
+	missing := a.ingredients(ingredients)
+	a.cookware()
+	a.timers()
+	return a.result(), missing
+}
@@ -0,0 +1,348 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// The largest file read from within an archive, in bytes
+const maxArchiveEntrySize = 64 << 20
+
+// A recipe read from another app's export, along with its categories and photo
+type ArchivedRecipe struct {
+	Recipe
+	Categories []string
+	Photo      []byte // The main photo of the recipe, nil if it has none
+	PhotoExt   string // The photo's extension, e.g. ".jpg"
+}
+
+// A recipe (or file) within an export which couldn't be converted
+type Failure struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Reads a whole file from a zip archive, up to `maxArchiveEntrySize`
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxArchiveEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxArchiveEntrySize {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+// Tests if a file name is a (web friendly) image
+func isImage(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".webp", ".gif":
+		return true
+	}
+	return false
+}
+
+// ---- Paprika ----
+
+// A single recipe within a Paprika export
+type paprikaRecipe struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Ingredients string   `json:"ingredients"`
+	Directions  string   `json:"directions"`
+	Notes       string   `json:"notes"`
+	Servings    string   `json:"servings"`
+	PrepTime    string   `json:"prep_time"`
+	CookTime    string   `json:"cook_time"`
+	TotalTime   string   `json:"total_time"`
+	Source      string   `json:"source"`
+	SourceURL   string   `json:"source_url"`
+	Categories  []string `json:"categories"`
+	Photo       string   `json:"photo"`      // File name of the photo
+	PhotoData   string   `json:"photo_data"` // Base64 encoded photo
+}
+
+// Reads a Paprika export (`.paprikarecipes`), a zip of gzipped JSON recipes.
+//
+// Recipes which can't be read are reported as failures, rather than failing
+// the whole archive.
+func ParsePaprika(archivePath string) ([]ArchivedRecipe, []Failure, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer archive.Close()
+
+	recipes := make([]ArchivedRecipe, 0)
+	failures := make([]Failure, 0)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		var r ArchivedRecipe
+		if r, err = readPaprikaRecipe(file); err != nil {
+			failures = append(failures, Failure{Name: file.Name, Reason: err.Error()})
+			continue
+		}
+		recipes = append(recipes, r)
+	}
+	return recipes, failures, nil
+}
+
+// Reads a single gzipped recipe from a Paprika export
+func readPaprikaRecipe(file *zip.File) (ArchivedRecipe, error) {
+	data, err := readZipFile(file)
+	if err != nil {
+		return ArchivedRecipe{}, err
+	}
+
+	var gz *gzip.Reader
+	if gz, err = gzip.NewReader(bytes.NewReader(data)); err != nil {
+		return ArchivedRecipe{}, err
+	}
+	defer gz.Close()
+
+	// Read one byte over the limit, as the recipe is only limited once unzipped
+	if data, err = io.ReadAll(io.LimitReader(gz, maxArchiveEntrySize+1)); err != nil {
+		return ArchivedRecipe{}, err
+	}
+	if len(data) > maxArchiveEntrySize {
+		return ArchivedRecipe{}, ErrTooLarge
+	}
+
+	var p paprikaRecipe
+	if err = json.Unmarshal(data, &p); err != nil {
+		return ArchivedRecipe{}, err
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return ArchivedRecipe{}, errors.New("Recipe has no name.")
+	}
+
+	source := p.SourceURL
+	if source == "" {
+		source = p.Source
+	}
+
+	r := ArchivedRecipe{
+		Recipe: Recipe{
+			Name:         strings.TrimSpace(p.Name),
+			Description:  p.Description,
+			Source:       source,
+			Servings:     servingsNumber(strings.TrimSpace(p.Servings)),
+			PrepTime:     asDuration(p.PrepTime),
+			CookTime:     asDuration(p.CookTime),
+			TotalTime:    asDuration(p.TotalTime),
+			Ingredients:  strings.Split(p.Ingredients, "\n"),
+			Instructions: strings.Split(p.Directions, "\n"),
+			Notes:        p.Notes,
+		},
+		Categories: p.Categories,
+	}
+
+	if p.PhotoData != "" {
+		if r.Photo, err = base64.StdEncoding.DecodeString(p.PhotoData); err != nil {
+			return ArchivedRecipe{}, fmt.Errorf("Invalid photo: %w", err)
+		}
+		r.PhotoExt = strings.ToLower(path.Ext(p.Photo))
+		if r.PhotoExt == "" {
+			r.PhotoExt = ".jpg"
+		}
+	}
+	return r, nil
+}
+
+// ---- Mealie ----
+
+// Reads a Mealie export, either a single recipe's JSON or a zip of them.
+//
+// Within a zip, each recipe's photo is found within an `images` folder next to
+// its JSON (e.g. `recipes/toast/toast.json`, `recipes/toast/images/original.webp`).
+// JSON files which aren't recipes are reported as failures.
+func ParseMealie(exportPath string) ([]ArchivedRecipe, []Failure, error) {
+	if !strings.EqualFold(path.Ext(exportPath), ".zip") {
+		data, err := os.ReadFile(exportPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		recipes, failures := parseMealieJSON(exportPath, data)
+		return recipes, failures, nil
+	}
+
+	archive, err := zip.OpenReader(exportPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer archive.Close()
+
+	// Photos, by the folder containing their `images` folder
+	photos := map[string]*zip.File{}
+	for _, file := range archive.File {
+		dir, name := path.Split(file.Name)
+		if !isImage(name) || path.Base(dir) != "images" {
+			continue
+		}
+		owner := path.Dir(path.Clean(dir))
+		// Prefer the original over resized copies
+		if _, ok := photos[owner]; !ok || strings.HasPrefix(name, "original") {
+			photos[owner] = file
+		}
+	}
+
+	recipes := make([]ArchivedRecipe, 0)
+	failures := make([]Failure, 0)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !strings.EqualFold(path.Ext(file.Name), ".json") {
+			continue
+		}
+
+		var data []byte
+		if data, err = readZipFile(file); err != nil {
+			failures = append(failures, Failure{Name: file.Name, Reason: err.Error()})
+			continue
+		}
+
+		fileRecipes, fileFailures := parseMealieJSON(file.Name, data)
+		failures = append(failures, fileFailures...)
+
+		// Photos only belong to a lone recipe in its own folder
+		if photo, ok := photos[path.Dir(file.Name)]; ok && len(fileRecipes) == 1 {
+			if fileRecipes[0].Photo, err = readZipFile(photo); err == nil {
+				fileRecipes[0].PhotoExt = strings.ToLower(path.Ext(photo.Name))
+			} else {
+				failures = append(failures, Failure{Name: photo.Name, Reason: err.Error()})
+			}
+		}
+		recipes = append(recipes, fileRecipes...)
+	}
+	return recipes, failures, nil
+}
+
+// Reads the recipes of a Mealie JSON file, which may hold one recipe, a list
+// of recipes or an object with a list of `recipes`.
+func parseMealieJSON(name string, data []byte) ([]ArchivedRecipe, []Failure) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		failure := Failure{Name: name, Reason: "Invalid JSON: " + err.Error()}
+		return nil, []Failure{failure}
+	}
+
+	if obj, ok := doc.(map[string]any); ok {
+		if list, ok := obj["recipes"].([]any); ok {
+			doc = list
+		}
+	}
+
+	recipes := make([]ArchivedRecipe, 0)
+	failures := make([]Failure, 0)
+	for i, item := range asList(doc) {
+		node, ok := item.(map[string]any)
+		if !ok || !isMealieRecipe(node) {
+			failures = append(failures, Failure{
+				Name:   fmt.Sprintf("%v (#%v)", name, i+1),
+				Reason: "Not a Mealie recipe.",
+			})
+			continue
+		}
+		recipes = append(recipes, mealieToRecipe(node))
+	}
+	return recipes, failures
+}
+
+// Tests if a JSON object looks like a Mealie recipe
+func isMealieRecipe(node map[string]any) bool {
+	_, hasIngredients := node["recipeIngredient"]
+	_, hasInstructions := node["recipeInstructions"]
+	return asText(node["name"]) != "" && (hasIngredients || hasInstructions)
+}
+
+// Reads a Mealie ingredient, which is either text or structured (depending
+// on the Mealie version).
+func mealieIngredient(value any) string {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return asText(value)
+	}
+
+	// Ingredients keep how they were first written
+	for _, key := range []string{"originalText", "display"} {
+		if text := asText(obj[key]); text != "" {
+			return text
+		}
+	}
+
+	parts := make([]string, 0, 4)
+	if qty, ok := obj["quantity"].(float64); ok && qty > 0 {
+		parts = append(parts, asText(qty))
+	}
+	parts = append(parts, asText(obj["unit"]), asText(obj["food"]))
+	text := strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+
+	if note := asText(obj["note"]); note != "" {
+		if text == "" {
+			return note
+		}
+		text += ", " + note
+	}
+	return text
+}
+
+// Converts a Mealie recipe node into an ArchivedRecipe (without its photo)
+func mealieToRecipe(node map[string]any) ArchivedRecipe {
+	r := ArchivedRecipe{
+		Recipe: Recipe{
+			Name:         asText(node["name"]),
+			Description:  asText(node["description"]),
+			Source:       asText(node["orgURL"]),
+			Servings:     servingsNumber(asText(node["recipeYield"])),
+			PrepTime:     asDuration(node["prepTime"]),
+			CookTime:     asDuration(node["performTime"]),
+			TotalTime:    asDuration(node["totalTime"]),
+			Tags:         asTexts(node["tags"]),
+			Instructions: instructionSteps(node["recipeInstructions"]),
+		},
+		Categories: asTexts(node["recipeCategory"]),
+	}
+	if r.CookTime == 0 {
+		r.CookTime = asDuration(node["cookTime"])
+	}
+
+	for _, ingr := range asList(node["recipeIngredient"]) {
+		if text := mealieIngredient(ingr); text != "" {
+			r.Ingredients = append(r.Ingredients, text)
+		}
+	}
+
+	notes := make([]string, 0)
+	for _, note := range asList(node["notes"]) {
+		obj, ok := note.(map[string]any)
+		if !ok {
+			notes = append(notes, asText(note))
+			continue
+		}
+		title, text := asText(obj["title"]), asText(obj["text"])
+		if title != "" {
+			text = title + ": " + text
+		}
+		notes = append(notes, text)
+	}
+	r.Notes = strings.Join(notes, "\n")
+
+	return r
+}
@@ -0,0 +1,142 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Writes a zip archive of `files` (by name) into a temporary directory
+func writeTestZip(t *testing.T, name string, files map[string][]byte) string {
+	archivePath := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	for name, data := range files {
+		entry, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Write(data)
+	}
+	if err = archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return archivePath
+}
+
+// Gzips `data`, as each Paprika recipe is
+func gzipBytes(data string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(data))
+	gz.Close()
+	return buf.Bytes()
+}
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestParsePaprika(t *testing.T) {
+	photo := base64.StdEncoding.EncodeToString([]byte("jpeg"))
+	archivePath := writeTestZip(t, "export.paprikarecipes", map[string][]byte{
+		"Toast.paprikarecipe": gzipBytes(`{"name": "Toast", "servings": "2 servings",
+			"ingredients": "For the toast:\n2 slices bread\n\n1 tbsp butter",
+			"directions": "Toast the bread.\n\nSpread with butter.",
+			"notes": "Use stale bread.", "prep_time": "5 mins",
+			"source_url": "https://example.com/toast", "categories": ["Breakfast", "Quick"],
+			"photo": "abc.JPG", "photo_data": "` + photo + `"}`),
+		"Broken.paprikarecipe": []byte("not gzipped"),
+	})
+
+	recipes, failures, err := ParsePaprika(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recipes) != 1 || len(failures) != 1 || failures[0].Name != "Broken.paprikarecipe" {
+		t.Fatalf("Wrong recipes or failures\ngot: %+v\n%+v", recipes, failures)
+	}
+
+	r := recipes[0]
+	if r.Name != "Toast" || r.Servings != "2" || r.PrepTime != 5*time.Minute ||
+		r.Source != "https://example.com/toast" || len(r.Categories) != 2 {
+		t.Fatalf("Wrong recipe details: %+v", r)
+	}
+	if string(r.Photo) != "jpeg" || r.PhotoExt != ".jpg" {
+		t.Fatalf("Wrong photo %q (%v)", r.Photo, r.PhotoExt)
+	}
+
+	src := ToCooklang(r.Recipe)
+	if !strings.Contains(src, "Toast the @bread{2%slices}.") ||
+		!strings.Contains(src, "[- Use stale bread. -]") || strings.Contains(src, "For the toast") {
+		t.Fatalf("Wrong cooklang:\n%v", src)
+	}
+}
+
+func TestParsePaprikaTooLarge(t *testing.T) {
+	// Small once zipped, but over the limit once unzipped
+	huge := `{"name": "Huge", "notes": "` + strings.Repeat(" ", maxArchiveEntrySize) + `"}`
+	archivePath := writeTestZip(t, "export.paprikarecipes", map[string][]byte{
+		"Huge.paprikarecipe": gzipBytes(huge),
+	})
+
+	recipes, failures, err := ParsePaprika(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recipes) != 0 || len(failures) != 1 || failures[0].Reason != ErrTooLarge.Error() {
+		t.Fatalf("Wrong recipes or failures\ngot: %+v\n%+v", recipes, failures)
+	}
+}
+
+func TestParseMealie(t *testing.T) {
+	archivePath := writeTestZip(t, "mealie.zip", map[string][]byte{
+		"recipes/toast/toast.json": []byte(`{"name": "Toast", "recipeYield": "2",
+			"recipeCategory": [{"name": "Breakfast"}], "tags": ["quick"],
+			"totalTime": "10 minutes",
+			"recipeIngredient": [
+				{"quantity": 2, "unit": {"name": "slices"}, "food": {"name": "bread"}, "note": ""},
+				{"originalText": "1 tbsp butter"},
+				{"note": "jam, to serve"}],
+			"recipeInstructions": [{"title": "", "text": "Toast the bread."},
+				{"text": "Spread with butter and jam."}],
+			"notes": [{"title": "Tip", "text": "Use stale bread."}]}`),
+		"recipes/toast/images/min-original.webp": []byte("small"),
+		"recipes/toast/images/original.webp":     []byte("webp"),
+		"database.json":                          []byte(`{"users": []}`),
+	})
+
+	recipes, failures, err := ParseMealie(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recipes) != 1 || len(failures) != 1 || !strings.HasPrefix(failures[0].Name, "database.json") {
+		t.Fatalf("Wrong recipes or failures\ngot: %+v\n%+v", recipes, failures)
+	}
+
+	r := recipes[0]
+	wantIngredients := "2 slices bread|1 tbsp butter|jam, to serve"
+	if got := strings.Join(r.Ingredients, "|"); got != wantIngredients {
+		t.Fatalf("Wrong ingredients\ngot: %v\nwant: %v", got, wantIngredients)
+	}
+	if r.Categories[0] != "Breakfast" || r.Tags[0] != "quick" || r.TotalTime != 10*time.Minute {
+		t.Fatalf("Wrong recipe details: %+v", r)
+	}
+	if string(r.Photo) != "webp" || r.PhotoExt != ".webp" {
+		t.Fatalf("Wrong photo %q (%v)", r.Photo, r.PhotoExt)
+	}
+	if r.Notes != "Tip: Use stale bread." {
+		t.Fatalf("Wrong notes %q", r.Notes)
+	}
+}
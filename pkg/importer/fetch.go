@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var ErrTooLarge = errors.New("Page exceeds the size limit.")
+var ErrPrivateAddress = errors.New("Refusing to fetch from a private address.")
+
+// Tests if `ip` is reachable from the public internet, rather than being
+// loopback, link-local, private (e.g. 192.168.0.0/16) or unspecified.
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast())
+}
+
+// Rejects connections to non-public addresses. This runs after the host is
+// resolved, so it also covers redirects and hostnames resolving to private
+// addresses.
+func dialPublicOnly(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w (%v)", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// Fetches the page at `pageURL` for importing, giving up after `timeout`.
+//
+// Only http(s) URLs are fetched. Unless `allowPrivate` is set, pages on
+// loopback, link-local or private addresses are refused with
+// ErrPrivateAddress. Returns ErrTooLarge if the page is larger than `limit`
+// bytes.
+func FetchPage(pageURL string, timeout time.Duration, limit int64, allowPrivate bool) ([]byte, error) {
+	var err error
+
+	var parsed *url.URL
+	if parsed, err = url.Parse(pageURL); err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("Unsupported URL scheme: %q", parsed.Scheme)
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = dialPublicOnly
+	}
+	client := http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}
+	var resp *http.Response
+	if resp, err = client.Get(parsed.String()); err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fetching %v failed: %v", pageURL, resp.Status)
+	}
+	if resp.ContentLength > limit {
+		return nil, ErrTooLarge
+	}
+
+	// Read one byte over the limit, to detect pages without a length
+	var data []byte
+	if data, err = io.ReadAll(io.LimitReader(resp.Body, limit+1)); err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
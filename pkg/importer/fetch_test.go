@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestFetchPage(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/page", http.StatusFound)
+			return
+		}
+		w.Write([]byte("<html></html>"))
+	}))
+	defer site.Close()
+
+	// Local pages are only fetched when allowed
+	if page, err := FetchPage(site.URL+"/redirect", time.Second, 1024, true); err != nil ||
+		string(page) != "<html></html>" {
+		t.Fatalf("Failed to fetch an allowed local page: %v %q", err, page)
+	}
+	if _, err := FetchPage(site.URL+"/page", time.Second, 8, true); err != ErrTooLarge {
+		t.Fatalf("Wrong error for a large page\ngot: %v\nwant: %v.", err, ErrTooLarge)
+	}
+	for _, pageURL := range []string{site.URL + "/page", "http://localhost:1/",
+		"http://169.254.169.254/latest/meta-data/", "http://[::1]:1/"} {
+		if _, err := FetchPage(pageURL, time.Second, 1024, false); !errors.Is(err, ErrPrivateAddress) {
+			t.Fatalf("Wrong error fetching %v\ngot: %v\nwant: %v.", pageURL, err, ErrPrivateAddress)
+		}
+	}
+	if _, err := FetchPage("file:///etc/passwd", time.Second, 1024, true); err == nil {
+		t.Fatalf("Fetched a file URL")
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+	} {
+		if got := isPublicIP(net.ParseIP(addr)); got != want {
+			t.Fatalf("Wrong publicity of %v\ngot: %v\nwant: %v.", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Package importer converts recipes from other formats (e.g. schema.org
+// JSON-LD embedded in web pages) into cooklang.
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+// A recipe read from another format, before conversion to cooklang.
+type Recipe struct {
+	Name        string
+	Description string
+	Author      string
+	Source      string // Where the recipe came from, usually a URL
+	Servings    string
+	PrepTime    time.Duration
+	CookTime    time.Duration
+	TotalTime   time.Duration
+	Tags        []string
+	Images      []string // URLs (or paths) of photos of the recipe
+	// Each ingredient as written, e.g. "2 cloves garlic, minced"
+	Ingredients []string
+	// Each step as plain text
+	Instructions []string
+	// Free-form notes, kept as a comment
+	Notes string
+}
+
+// An ingredient parsed from a written ingredient line.
+type Ingredient struct {
+	Name string
+	Qty  string // Empty if the line has no quantity
+	Unit string
+	Note string // Anything after the name, e.g. "minced" or "(optional)"
+}
+
+// Vulgar fractions and their ascii equivalents
+var vulgarFractions = strings.NewReplacer(
+	"¼", " 1/4", "½", " 1/2", "¾", " 3/4", "⅓", " 1/3", "⅔", " 2/3",
+	"⅛", " 1/8", "⅜", " 3/8", "⅝", " 5/8", "⅞", " 7/8", "⅕", " 1/5",
+	"⁄", "/",
+)
+
+// Matches the quantity at the start of an ingredient line, including mixed
+// numbers (1 1/2) and ranges (2-3)
+var qtyRegex = regexp.MustCompile(
+	`^(\d+/\d+|\d+(?:[.,]\d+)?(?:\s+\d+/\d+)?)(?:\s*(?:-|–|to)\s*(\d+(?:[.,]\d+)?(?:/\d+)?))?\s*`)
+
+// Matches a parenthesised note before an ingredient's name
+var leadingNoteRegex = regexp.MustCompile(`^\(([^)]*)\)\s*`)
+
+// Words describing the size of an ingredient, e.g. "2 large eggs"
+var sizeWords = map[string]bool{
+	"small": true, "medium": true, "large": true, "extra-large": true, "big": true,
+}
+
+// Characters with special meaning in cooklang, removed from names and amounts
+var cooklangSpecial = strings.NewReplacer("@", "", "#", "", "~", "", "{", "", "}", "", "%", "")
+
+// Swaps characters which would start a component or comment in cooklang for
+// look-alikes, so imported text reads the same but stays text
+var textSpecial = strings.NewReplacer(
+	"@", "＠", "#", "＃", "~", "～", "--", "–", "[-", "[–")
+
+// Neutralizes cooklang syntax within a line of plain text (see `textSpecial`),
+// including a leading `>>`, which would read as metadata.
+//
+// e.g. "Email chef@example.com -- or" -> "Email chef＠example.com – or"
+func escapeText(text string) string {
+	text = textSpecial.Replace(text)
+	if strings.HasPrefix(text, ">>") {
+		text = "> >" + text[2:]
+	}
+	return text
+}
+
+// Normalizes whitespace and vulgar fractions within a line of text
+func cleanText(text string) string {
+	return strings.Join(strings.Fields(vulgarFractions.Replace(text)), " ")
+}
+
+// Parses a written ingredient line into its quantity, unit and name.
+//
+// e.g. "1 ½ cups plain flour, sifted" -> {"plain flour", "1.5", "cups", "sifted"}
+//
+// Ranges keep the upper bound, e.g. "2-3 cloves" -> 3 cloves. Lines without a
+// recognised quantity are treated as a name alone.
+func ParseIngredientLine(line string) Ingredient {
+	line = strings.TrimSpace(cleanText(line))
+	ingr := Ingredient{}
+
+	if match := qtyRegex.FindStringSubmatch(line); match != nil {
+		ingr.Qty = match[1]
+		if match[2] != "" {
+			ingr.Qty = match[2]
+		}
+		// Mixed numbers are summed, as cooklang quantities are a single number
+		if parts := strings.Fields(ingr.Qty); len(parts) == 2 {
+			whole := cook.TryParseQty(parts[0])
+			frac := cook.TryParseQty(parts[1])
+			if whole != cook.NoQty && frac != cook.NoQty {
+				ingr.Qty = cook.FormatQty(whole + frac)
+			}
+		}
+		ingr.Qty = strings.Replace(ingr.Qty, ",", ".", 1)
+		line = line[len(match[0]):]
+	}
+
+	// Notes follow a comma or are in parentheses, e.g. "1 (28 oz) can tomatoes"
+	notes := make([]string, 0)
+	stripNote := func() {
+		if match := leadingNoteRegex.FindStringSubmatch(line); match != nil {
+			notes = append(notes, match[1])
+			line = line[len(match[0]):]
+		}
+	}
+	stripNote()
+
+	// Units of two words first (e.g. "fl oz"), then one
+	if ingr.Qty != "" {
+		words := strings.Fields(line)
+		for n := 2; n >= 1; n-- {
+			if len(words) <= n {
+				continue
+			}
+			unit := strings.Join(words[:n], " ")
+			if units.Known(strings.Trim(unit, "()")) {
+				ingr.Unit = strings.Trim(unit, "()")
+				line = strings.Join(words[n:], " ")
+				break
+			}
+		}
+	}
+	stripNote()
+	line = strings.TrimPrefix(line, "of ")
+
+	name := line
+	if i := strings.IndexAny(line, ",("); i > 0 {
+		name = line[:i]
+		notes = append(notes, strings.Trim(strings.TrimSpace(line[i:]), ", "))
+	}
+
+	// Sizes describe the ingredient, rather than name it
+	words := strings.Fields(name)
+	if len(words) > 1 && sizeWords[strings.ToLower(words[0])] {
+		notes = append([]string{words[0]}, notes...)
+		name = strings.Join(words[1:], " ")
+	}
+	ingr.Note = strings.Join(notes, ", ")
+	ingr.Name = strings.TrimSpace(cooklangSpecial.Replace(name))
+	ingr.Unit = cooklangSpecial.Replace(ingr.Unit)
+	return ingr
+}
+
+// Formats a duration for metadata, e.g. "1 hour 30 minutes"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	parts := make([]string, 0, 2)
+	if hours == 1 {
+		parts = append(parts, "1 hour")
+	} else if hours > 1 {
+		parts = append(parts, fmt.Sprintf("%d hours", hours))
+	}
+	if minutes > 0 || hours == 0 {
+		parts = append(parts, fmt.Sprintf("%d minutes", minutes))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Removes line breaks from metadata values, which would end the metadata
+func metadataValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// Converts an imported recipe into cooklang source.
+//
+// Metadata is written for the source, author, servings, times and tags. The
+// instructions are annotated by `Annotate`; ingredients which are never
+// mentioned are listed in a step of their own, so none are lost. Notes are kept
+// as a block comment at the end.
+func ToCooklang(r Recipe) string {
+	var out strings.Builder
+
+	meta := func(key string, value string) {
+		if value = metadataValue(value); value != "" {
+			fmt.Fprintf(&out, ">> %s: %s\n", key, value)
+		}
+	}
+	meta("source", r.Source)
+	meta("author", r.Author)
+	meta("description", r.Description)
+	meta("servings", r.Servings)
+	if r.PrepTime > 0 {
+		meta("prep time", formatDuration(r.PrepTime))
+	}
+	if r.CookTime > 0 {
+		meta("cook time", formatDuration(r.CookTime))
+	}
+	if r.TotalTime > 0 {
+		meta("time", formatDuration(r.TotalTime))
+	}
+	meta("tags", strings.Join(r.Tags, ", "))
+	if out.Len() > 0 {
+		out.WriteString("\n")
+	}
+
+	ingredients := make([]Ingredient, 0, len(r.Ingredients))
+	for _, line := range r.Ingredients {
+		// Skip section headings, e.g. "For the sauce:"
+		if line = strings.TrimSpace(line); line == "" || strings.HasSuffix(line, ":") {
+			continue
+		}
+		ingredients = append(ingredients, ParseIngredientLine(line))
+	}
+
+	steps := make([]string, 0, len(r.Instructions))
+	for _, step := range r.Instructions {
+		if step = cleanText(step); step != "" {
+			steps = append(steps, step)
+		}
+	}
+
+	steps, missing := Annotate(steps, ingredients)
+	if len(missing) > 0 {
+		mentions := make([]string, 0, len(missing))
+		for _, ingr := range missing {
+			mentions = append(mentions, formatIngredient(ingr.Name, ingr))
+		}
+		steps = append([]string{"Prepare " + strings.Join(mentions, ", ") + "."}, steps...)
+	}
+
+	out.WriteString(strings.Join(steps, "\n\n"))
+	out.WriteString("\n")
+
+	if notes := strings.TrimSpace(r.Notes); notes != "" {
+		notes = strings.ReplaceAll(notes, "-]", "- ]")
+		fmt.Fprintf(&out, "\n[- %s -]\n", notes)
+	}
+	return out.String()
+}
+
+// Reads the number of servings from a written yield, e.g. "4 servings" -> "4".
+// Yields without a leading number are returned as is.
+func servingsNumber(yield string) string {
+	if match := leadingNumberRegex.FindString(yield); match != "" {
+		return match
+	}
+	return yield
+}
+
+// Converts a recipe name into a file name, e.g. "Eggs Benedict!" -> "eggs_benedict"
+func FileName(name string) string {
+	var out strings.Builder
+	lastUnderscore := true
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			out.WriteRune(r)
+			lastUnderscore = false
+		case !lastUnderscore:
+			out.WriteRune('_')
+			lastUnderscore = true
+		}
+	}
+
+	fileName := strings.Trim(out.String(), "_")
+	if fileName == "" {
+		return "imported_recipe"
+	}
+	return fileName
+}
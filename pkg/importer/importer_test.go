@@ -0,0 +1,248 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// A saved page, with the recipe nested within a @graph
+const testPage = `<html><head>
+<link rel="canonical" href="https://example.com/shakshuka">
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@graph": [
+  {"@type": "WebSite", "name": "Example"},
+  {"@type": ["Recipe"], "name": "Easy Shakshuka",
+   "author": [{"@type": "Person", "name": "Sam Cook"}],
+   "recipeYield": ["4", "4 servings"], "prepTime": "PT10M", "totalTime": "PT1H5M",
+   "keywords": "Eggs, vegetarian",
+   "recipeIngredient": ["2 tbsp olive oil", "1 (28 oz) can crushed tomatoes",
+     "6 large eggs", "Salt"],
+   "recipeInstructions": [
+     {"@type": "HowToSection", "name": "Sauce", "itemListElement": [
+       {"@type": "HowToStep", "text": "Heat the oil, then add the tomatoes &amp; simmer."}]},
+     {"@type": "HowToStep", "text": "Crack in each egg."}]}]}
+</script></head><body></body></html>`
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestParseIngredientLine(t *testing.T) {
+	testParse := func(line string, want Ingredient) {
+		got := ParseIngredientLine(line)
+		if got != want {
+			t.Fatalf("Failed to parse ingredient %q\ngot: %+v\nwant: %+v.", line, got, want)
+		}
+	}
+
+	testParse("2 cups flour", Ingredient{Name: "flour", Qty: "2", Unit: "cups"})
+	testParse("1 ½ cups plain flour, sifted",
+		Ingredient{Name: "plain flour", Qty: "1.5", Unit: "cups", Note: "sifted"})
+	testParse("1/2 tsp salt", Ingredient{Name: "salt", Qty: "1/2", Unit: "tsp"})
+	testParse("2-3 cloves garlic", Ingredient{Name: "garlic", Qty: "3", Unit: "cloves"})
+	testParse("400g tinned tomatoes", Ingredient{Name: "tinned tomatoes", Qty: "400", Unit: "g"})
+	testParse("1 cup (240 ml) milk", Ingredient{Name: "milk", Qty: "1", Unit: "cup", Note: "240 ml"})
+	testParse("3 large eggs", Ingredient{Name: "eggs", Qty: "3", Note: "large"})
+	testParse("Salt and pepper", Ingredient{Name: "Salt and pepper"})
+}
+
+func TestParseJSONLD(t *testing.T) {
+	r, err := ParseJSONLD([]byte(testPage))
+	if err != nil {
+		t.Fatalf("Failed to find recipe: %v", err)
+	}
+
+	if r.Name != "Easy Shakshuka" || r.Author != "Sam Cook" || r.Servings != "4" {
+		t.Fatalf("Wrong recipe details: %+v", r)
+	}
+	if r.Source != "https://example.com/shakshuka" {
+		t.Fatalf("Wrong source, got: %q", r.Source)
+	}
+	if r.PrepTime != 10*time.Minute || r.TotalTime != 65*time.Minute {
+		t.Fatalf("Wrong times, got: %v, %v", r.PrepTime, r.TotalTime)
+	}
+	if strings.Join(r.Tags, ",") != "eggs,vegetarian" {
+		t.Fatalf("Wrong tags, got: %v", r.Tags)
+	}
+
+	wantSteps := []string{"Heat the oil, then add the tomatoes & simmer.", "Crack in each egg."}
+	if strings.Join(r.Instructions, "|") != strings.Join(wantSteps, "|") {
+		t.Fatalf("Wrong instructions\ngot: %q\nwant: %q", r.Instructions, wantSteps)
+	}
+
+	// Plain JSON-LD documents work alike
+	if _, err = ParseJSONLD([]byte(`{"@type": "Recipe", "name": "x"}`)); err != nil {
+		t.Fatalf("Failed to read JSON-LD document: %v", err)
+	}
+	if _, err = ParseJSONLD([]byte(`<html></html>`)); err != ErrNoRecipe {
+		t.Fatalf("Found a recipe in an empty page")
+	}
+}
+
+func TestToCooklang(t *testing.T) {
+	r, _ := ParseJSONLD([]byte(testPage))
+	src := ToCooklang(r)
+	recipe := cook.ParseRecipeString("", src)
+
+	if servings, ok := cook.RecipeServings(&recipe); !ok || servings != 4 {
+		t.Fatalf("Servings were not written\n%v", src)
+	}
+	if duration, _ := recipe.Metadata.Get("time"); duration != "1 hour 5 minutes" {
+		t.Fatalf("Wrong total time %q\n%v", duration, src)
+	}
+
+	// Every ingredient is kept, mentions or not
+	want := map[string]string{
+		"olive oil":        "2 tbsp",
+		"crushed tomatoes": "1 can",
+		"egg":              "6 ",
+		"Salt":             " ",
+	}
+	if len(recipe.Ingredients) != len(want) {
+		t.Fatalf("Wrong ingredients %+v\n%v", recipe.Ingredients, src)
+	}
+	for _, ingr := range recipe.Ingredients {
+		if amount, ok := want[ingr.Name]; !ok || amount != ingr.Qty+" "+ingr.Unit {
+			t.Fatalf("Unexpected ingredient %+v\n%v", ingr, src)
+		}
+	}
+
+	// Mentions are annotated in place
+	if !strings.Contains(src, "Heat the @olive oil{2%tbsp}, then add the @crushed tomatoes{1%can}") {
+		t.Fatalf("Mentions were not annotated\n%v", src)
+	}
+}
+
+func TestToCooklangEscapes(t *testing.T) {
+	r := Recipe{
+		Ingredients:  []string{"1 cup milk"},
+		Instructions: []string{"Email chef@example.com -- or tag #dinner, heat milk ~5 min [- note", ">> not: metadata"},
+	}
+	src := ToCooklang(r)
+	recipe := cook.ParseRecipeString("", src)
+
+	if len(recipe.Metadata) != 0 {
+		t.Fatalf("Text was read as metadata %+v\n%v", recipe.Metadata, src)
+	}
+	if len(recipe.Ingredients) != 1 || recipe.Ingredients[0].Name != "milk" || len(recipe.Cookware) != 0 {
+		t.Fatalf("Wrong components %+v, %+v\n%v", recipe.Ingredients, recipe.Cookware, src)
+	}
+	if len(recipe.Timers) != 1 || recipe.Timers[0].Qty != "5" || recipe.Timers[0].Unit != "min" {
+		t.Fatalf("Wrong timers %+v\n%v", recipe.Timers, src)
+	}
+
+	// Every word survives, as text rather than comments or components
+	if len(recipe.Steps) != 2 {
+		t.Fatalf("Wrong number of steps %v\n%v", len(recipe.Steps), src)
+	}
+	var text strings.Builder
+	for _, chunk := range recipe.Steps[0] {
+		if _, ok := chunk.(cook.Text); ok {
+			text.WriteString(chunk.ToString())
+		}
+	}
+	for _, word := range []string{"chef＠example.com", "– or", "＃dinner", "[– note"} {
+		if !strings.Contains(text.String(), word) {
+			t.Fatalf("Missing %q from step\ngot: %q\n%v", word, text.String(), src)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	if got := FileName("Mom's Best Apple-Pie!"); got != "mom_s_best_apple_pie" {
+		t.Fatalf("Wrong file name, got: %q", got)
+	}
+	if got := FileName("!!"); got != "imported_recipe" {
+		t.Fatalf("Wrong fallback file name, got: %q", got)
+	}
+}
+
+func TestParsePlainText(t *testing.T) {
+	testParse := func(text string, want Recipe) {
+		got := ParsePlainText(text)
+		if got.Name != want.Name || got.Servings != want.Servings || got.Notes != want.Notes ||
+			strings.Join(got.Ingredients, "|") != strings.Join(want.Ingredients, "|") ||
+			strings.Join(got.Instructions, "|") != strings.Join(want.Instructions, "|") {
+			t.Fatalf("Failed to parse plain text\n%v\ngot: %+v\nwant: %+v.", text, got, want)
+		}
+	}
+
+	// Without headings
+	testParse("Banana Bread\nServes 8\n\n2 cups flour\n1/2 tsp salt\n\n"+
+		"Mix the flour and salt.\nBake 60 minutes.\n",
+		Recipe{
+			Name:         "Banana Bread",
+			Servings:     "8",
+			Ingredients:  []string{"2 cups flour", "1/2 tsp salt"},
+			Instructions: []string{"Mix the flour and salt.", "Bake 60 minutes."},
+		})
+
+	// With headings and list markers
+	testParse("Tomato soup\n\nIngredients:\n- 1 onion\n- Salt\n\nMethod:\n"+
+		"1. Fry the onion.\nStep 2: Season with salt.\n\nNotes:\nFreezes well.",
+		Recipe{
+			Name:         "Tomato soup",
+			Ingredients:  []string{"1 onion", "Salt"},
+			Instructions: []string{"Fry the onion.", "Season with salt."},
+			Notes:        "Freezes well.",
+		})
+}
+
+func TestAnnotate(t *testing.T) {
+	steps := []string{
+		"Preheat the oven and grease a loaf tin.",
+		"Mix the flour in a bowl, then bake 20-25 minutes in the tin.",
+	}
+	ingredients := []Ingredient{{Name: "flour", Qty: "2", Unit: "cups"}, {Name: "sugar"}}
+
+	got, missing := Annotate(steps, ingredients)
+	want := []string{
+		"Preheat the #oven{} and grease a #loaf tin{}.",
+		"Mix the @flour{2%cups} in a #bowl{}, then bake ~{25%minutes} in the tin.",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("Wrong annotations\ngot: %q\nwant: %q", got, want)
+	}
+	if len(missing) != 1 || missing[0].Name != "sugar" {
+		t.Fatalf("Wrong missing ingredients, got: %+v", missing)
+	}
+}
+
+func TestAnnotateEscapes(t *testing.T) {
+	steps := []string{
+		"Email chef@example.com -- or tag #salt, heat the milk ~5 min [- note",
+		">> butter: soft",
+	}
+	ingredients := []Ingredient{{Name: "salt"}, {Name: "milk", Qty: "1", Unit: "cup"},
+		{Name: "example"}, {Name: "butter"}}
+
+	got, missing := Annotate(steps, ingredients)
+	want := []string{
+		"Email chef＠@example{}.com – or tag ＃@salt{}, heat the @milk{1%cup} ~{5%min} [– note",
+		"> > @butter{}: soft",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("Wrong annotations\ngot: %q\nwant: %q", got, want)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("Wrong missing ingredients, got: %+v", missing)
+	}
+
+	// Each annotation parses as the component it was meant to be
+	recipe := cook.ParseRecipeString("", strings.Join(got, "\n\n"))
+	if len(recipe.Ingredients) != 4 || len(recipe.Cookware) != 0 || len(recipe.Timers) != 1 ||
+		len(recipe.Metadata) != 0 {
+		t.Fatalf("Wrong components %+v\n%v", recipe, strings.Join(got, "\n\n"))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := Diff("a\n\nb\nc\n", "a\n\nB\nc\n")
+	want := "  a\n- \n- b\n+ \n+ B\n  c\n"
+	if got != want {
+		t.Fatalf("Wrong diff\ngot:\n%v\nwant:\n%v", got, want)
+	}
+}
@@ -0,0 +1,273 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+var ErrNoRecipe = errors.New("No schema.org Recipe was found.")
+
+// Matches each JSON-LD block within an HTML page
+var jsonLDRegex = regexp.MustCompile(
+	`(?is)<script[^>]*type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+
+// Matches the canonical URL of a page, from its link or OpenGraph metadata
+var pageURLRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)<link[^>]*rel=["']canonical["'][^>]*href=["']([^"']+)["']`),
+	regexp.MustCompile(`(?i)<meta[^>]*property=["']og:url["'][^>]*content=["']([^"']+)["']`),
+}
+
+// Matches the number at the start of a yield, e.g. "4 servings" -> "4"
+var leadingNumberRegex = regexp.MustCompile(`^\d+(?:\.\d+)?`)
+
+// Matches HTML tags, which some sites leave within text values
+var tagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// Matches an ISO 8601 duration, e.g. "PT1H30M" or "P0DT45M"
+var isoDurationRegex = regexp.MustCompile(
+	`^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// Reads a schema.org `Recipe` from either an HTML page (from its embedded
+// JSON-LD) or a JSON-LD document.
+//
+// The recipe may be nested within a `@graph` or a list of entities. Returns
+// ErrNoRecipe if none is found.
+func ParseJSONLD(data []byte) (Recipe, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return parseJSONLDBlock(trimmed)
+	}
+
+	for _, match := range jsonLDRegex.FindAllSubmatch(data, -1) {
+		if r, err := parseJSONLDBlock(match[1]); err == nil {
+			if r.Source == "" {
+				r.Source = pageURL(data)
+			}
+			return r, nil
+		}
+	}
+	return Recipe{}, ErrNoRecipe
+}
+
+// Returns the canonical URL of an HTML page (saved pages keep this), or ""
+func pageURL(page []byte) string {
+	for _, regex := range pageURLRegexes {
+		if match := regex.FindSubmatch(page); match != nil {
+			return html.UnescapeString(string(match[1]))
+		}
+	}
+	return ""
+}
+
+// Decodes a single JSON-LD block and converts the first recipe within it
+func parseJSONLDBlock(block []byte) (Recipe, error) {
+	var doc any
+	if err := json.Unmarshal(block, &doc); err != nil {
+		return Recipe{}, fmt.Errorf("Invalid JSON-LD: %w", err)
+	}
+
+	node := findRecipeNode(doc)
+	if node == nil {
+		return Recipe{}, ErrNoRecipe
+	}
+	return schemaToRecipe(node), nil
+}
+
+// Tests if a JSON-LD node has the @type `name`, which may be one of many
+func hasType(node map[string]any, name string) bool {
+	for _, t := range asList(node["@type"]) {
+		if s, ok := t.(string); ok && strings.EqualFold(strings.TrimPrefix(s, "schema:"), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Searches a decoded JSON-LD document for the first `Recipe` node
+func findRecipeNode(doc any) map[string]any {
+	switch doc := doc.(type) {
+	case []any:
+		for _, item := range doc {
+			if node := findRecipeNode(item); node != nil {
+				return node
+			}
+		}
+	case map[string]any:
+		if hasType(doc, "Recipe") {
+			return doc
+		}
+		if graph, ok := doc["@graph"]; ok {
+			return findRecipeNode(graph)
+		}
+		// e.g. a `WebPage` with the recipe as its main entity
+		if entity, ok := doc["mainEntity"]; ok {
+			return findRecipeNode(entity)
+		}
+	}
+	return nil
+}
+
+// Wraps single values in a list, as JSON-LD allows either
+func asList(value any) []any {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		return value
+	default:
+		return []any{value}
+	}
+}
+
+// Cleans a text value: unescapes entities, removes HTML tags and
+// normalizes whitespace
+func cleanValue(text string) string {
+	text = html.UnescapeString(tagRegex.ReplaceAllString(text, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Reads a value as text. Objects use their `name` (or `text`, `url`), and lists
+// their first entry.
+func asText(value any) string {
+	switch value := value.(type) {
+	case string:
+		return cleanValue(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []any:
+		if len(value) > 0 {
+			return asText(value[0])
+		}
+	case map[string]any:
+		for _, key := range []string{"name", "text", "url", "@id"} {
+			if text := asText(value[key]); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// Reads every entry of a value as text
+func asTexts(value any) []string {
+	texts := make([]string, 0)
+	for _, item := range asList(value) {
+		if text := asText(item); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+// Reads a duration, in ISO 8601 format or as written (see `units.ParseDuration`)
+func asDuration(value any) time.Duration {
+	text := strings.ToUpper(asText(value))
+	if match := isoDurationRegex.FindStringSubmatch(text); match != nil && text != "P" {
+		var total time.Duration
+		for i, unit := range []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second} {
+			if val, err := strconv.ParseFloat(match[i+1], 64); err == nil {
+				total += time.Duration(val * float64(unit))
+			}
+		}
+		return total
+	}
+
+	if d, err := units.ParseDuration(asText(value)); err == nil {
+		return d
+	}
+	return 0
+}
+
+// Reads `recipeInstructions`, which may be plain text, a list of text, a list
+// of `HowToStep`s or `HowToSection`s of steps.
+func instructionSteps(value any) []string {
+	steps := make([]string, 0)
+	for _, item := range asList(value) {
+		switch item := item.(type) {
+		case string:
+			// Plain text instructions are often a single block of lines
+			for _, line := range strings.Split(item, "\n") {
+				if line = cleanValue(line); line != "" {
+					steps = append(steps, line)
+				}
+			}
+		case map[string]any:
+			if hasType(item, "HowToSection") {
+				steps = append(steps, instructionSteps(item["itemListElement"])...)
+				continue
+			}
+			text := asText(item["text"])
+			if text == "" {
+				text = asText(item["name"])
+			}
+			if text != "" {
+				steps = append(steps, text)
+			}
+		}
+	}
+	return steps
+}
+
+// Reads keywords, which are comma delimited text or a list
+func keywordList(value any) []string {
+	keywords := make([]string, 0)
+	for _, text := range asTexts(value) {
+		for _, keyword := range strings.Split(text, ",") {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = append(keywords, keyword)
+			}
+		}
+	}
+	return keywords
+}
+
+// Converts a schema.org `Recipe` node into a Recipe
+func schemaToRecipe(node map[string]any) Recipe {
+	r := Recipe{
+		Name:         asText(node["name"]),
+		Description:  asText(node["description"]),
+		Author:       asText(node["author"]),
+		PrepTime:     asDuration(node["prepTime"]),
+		CookTime:     asDuration(node["cookTime"]),
+		TotalTime:    asDuration(node["totalTime"]),
+		Images:       asTexts(node["image"]),
+		Ingredients:  asTexts(node["recipeIngredient"]),
+		Instructions: instructionSteps(node["recipeInstructions"]),
+	}
+
+	// Older pages use `ingredients`
+	if len(r.Ingredients) == 0 {
+		r.Ingredients = asTexts(node["ingredients"])
+	}
+
+	// The yield is often repeated as a number and text, e.g. ["4", "4 servings"]
+	r.Servings = servingsNumber(asText(node["recipeYield"]))
+
+	r.Source = asText(node["url"])
+	if r.Source == "" {
+		r.Source = asText(node["mainEntityOfPage"])
+	}
+
+	// Tags from categories, cuisines and keywords, without duplicates
+	seen := map[string]bool{}
+	for _, key := range []string{"recipeCategory", "recipeCuisine", "keywords"} {
+		for _, tag := range keywordList(node[key]) {
+			tag = strings.ToLower(tag)
+			if !seen[tag] {
+				seen[tag] = true
+				r.Tags = append(r.Tags, tag)
+			}
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,103 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matches section headings of plain text recipes, e.g. "Ingredients:"
+var headingRegex = regexp.MustCompile(
+	`(?i)^(ingredients|method|directions|instructions|steps|preparation|notes)\s*:?$`)
+
+// Matches list markers at the start of a line, e.g. "- ", "* ", "1. " or "Step 2:"
+var listMarkerRegex = regexp.MustCompile(`^(?:[-*•·]\s+|\d+[.)]\s+|(?i:step)\s*\d+\s*[:.)-]?\s*)`)
+
+// Matches the servings of plain text recipes, e.g. "Serves 4" or "Servings: 4"
+var servingsLineRegex = regexp.MustCompile(`(?i)^(?:serves|servings|makes|yield)\s*:?\s*(\d+.*)$`)
+
+// Tests if a (marker-less) line looks like an ingredient, i.e. it is short and
+// starts with a quantity.
+func looksLikeIngredient(line string) bool {
+	return len(line) <= 80 && qtyRegex.MatchString(cleanText(line))
+}
+
+// Parses a plain text recipe: an optional title, a block of ingredients (one
+// per line) and the method.
+//
+// Sections are found by their headings ("Ingredients", "Method", ...) if they
+// have them. Otherwise the first run of lines which look like ingredients
+// (e.g. "2 cups flour" or "- salt") is the ingredient block and the lines
+// after it are the method.
+func ParsePlainText(text string) Recipe {
+	r := Recipe{}
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	section := ""
+	hasHeadings := false
+	for _, line := range lines {
+		if headingRegex.MatchString(strings.TrimSpace(line)) {
+			hasHeadings = true
+			break
+		}
+	}
+
+	notes := make([]string, 0)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			// Blank lines end the ingredient block, when it has no heading
+			if !hasHeadings && section == "ingredients" {
+				section = "method"
+			}
+			continue
+		}
+
+		if match := headingRegex.FindStringSubmatch(line); match != nil {
+			section = strings.ToLower(match[1])
+			if section != "ingredients" && section != "notes" {
+				section = "method"
+			}
+			continue
+		}
+		if match := servingsLineRegex.FindStringSubmatch(line); match != nil && r.Servings == "" {
+			r.Servings = servingsNumber(match[1])
+			continue
+		}
+
+		marker := listMarkerRegex.FindString(line)
+		content := strings.TrimSpace(line[len(marker):])
+
+		switch section {
+		case "":
+			// The title comes before anything else
+			isIngredient := looksLikeIngredient(content) ||
+				(marker != "" && !strings.ContainsAny(marker, "0123456789"))
+			if !hasHeadings && isIngredient {
+				section = "ingredients"
+				r.Ingredients = append(r.Ingredients, content)
+			} else if r.Name == "" {
+				r.Name = line
+			} else if !hasHeadings {
+				section = "method"
+				r.Instructions = append(r.Instructions, content)
+			} else {
+				r.Description = strings.TrimSpace(r.Description + " " + line)
+			}
+		case "ingredients":
+			// Without headings, the block ends at the first non-ingredient
+			if !hasHeadings && marker == "" && !looksLikeIngredient(content) {
+				section = "method"
+				r.Instructions = append(r.Instructions, content)
+				continue
+			}
+			r.Ingredients = append(r.Ingredients, content)
+		case "method":
+			r.Instructions = append(r.Instructions, content)
+		case "notes":
+			notes = append(notes, line)
+		}
+	}
+
+	r.Notes = strings.Join(notes, "\n")
+	return r
+}
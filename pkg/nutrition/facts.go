@@ -0,0 +1,53 @@
+package nutrition
+
+import (
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// An ingredient left out of a nutrition calculation, and why
+type Unmatched struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"` // e.g. "not in the nutrient table"
+}
+
+// The nutrition of a recipe
+type Facts struct {
+	Total      Nutrients   `json:"total"`
+	Servings   float64     `json:"servings,omitempty"`
+	PerServing *Nutrients  `json:"perServing,omitempty"` // nil if servings are unknown
+	Unmatched  []Unmatched `json:"unmatched"`
+}
+
+// Sums the nutrition of `ingredients`, and per serving if `servings` isn't
+// `cook.NoQty`.
+//
+// Ingredients which aren't in the table, have no quantity (e.g. "salt") or
+// whose amount can't be weighed are listed in `Unmatched`, rather than counted.
+func (db DB) Calculate(ingredients []cook.Ingredient, servings float64) Facts {
+	facts := Facts{Unmatched: []Unmatched{}}
+	for _, ingr := range ingredients {
+		food, ok := db.Lookup(ingr.Name)
+		if !ok {
+			facts.Unmatched = append(facts.Unmatched, Unmatched{ingr.Name, "not in the nutrient table"})
+			continue
+		}
+		if ingr.QtyVal == cook.NoQty {
+			facts.Unmatched = append(facts.Unmatched, Unmatched{ingr.Name, "no quantity"})
+			continue
+		}
+
+		grams, err := food.Grams(ingr.QtyVal, ingr.Unit)
+		if err != nil {
+			facts.Unmatched = append(facts.Unmatched, Unmatched{ingr.Name, err.Error()})
+			continue
+		}
+		facts.Total = facts.Total.Add(food.Per100g.Scale(grams / 100))
+	}
+
+	if servings != cook.NoQty && servings > 0 {
+		perServing := facts.Total.Scale(1 / servings)
+		facts.Servings = servings
+		facts.PerServing = &perServing
+	}
+	return facts
+}
@@ -0,0 +1,325 @@
+// Package nutrition calculates the nutrition of recipes from a local nutrient
+// table, as exported from USDA FoodData Central (or written by hand).
+//
+// Tables are CSV files with a header row naming the columns, per 100 g:
+//
+//	description,Energy (kcal),Protein (g),Total lipid (fat) (g),Carbohydrate (g),Fiber (g),density,piece
+//	"Wheat flour, white, all-purpose",364,10.33,0.98,76.31,2.7,0.53,
+//	"Egg, whole, raw",143,12.56,9.51,0.72,0,,50
+//
+// `density` (g/ml) converts volumes into weights, and `piece` (g) converts
+// counts (e.g. "2 eggs" or "3 cloves"). Other columns are ignored.
+package nutrition
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+var ErrNoName = errors.New("Nutrient table has no name or description column.")
+
+// The nutrients tracked, per 100 g of a food or in total
+type Nutrients struct {
+	Calories float64 `json:"calories"` // kcal
+	Protein  float64 `json:"protein"`  // g
+	Fat      float64 `json:"fat"`      // g
+	Carbs    float64 `json:"carbs"`    // g
+	Fibre    float64 `json:"fibre"`    // g
+}
+
+// Returns the sum of two sets of nutrients
+func (n Nutrients) Add(other Nutrients) Nutrients {
+	return Nutrients{
+		Calories: n.Calories + other.Calories,
+		Protein:  n.Protein + other.Protein,
+		Fat:      n.Fat + other.Fat,
+		Carbs:    n.Carbs + other.Carbs,
+		Fibre:    n.Fibre + other.Fibre,
+	}
+}
+
+// Returns nutrients multiplied by `factor`
+func (n Nutrients) Scale(factor float64) Nutrients {
+	return Nutrients{
+		Calories: n.Calories * factor,
+		Protein:  n.Protein * factor,
+		Fat:      n.Fat * factor,
+		Carbs:    n.Carbs * factor,
+		Fibre:    n.Fibre * factor,
+	}
+}
+
+// An entry of the nutrient table
+type Food struct {
+	Name    string
+	Per100g Nutrients
+	Density float64 // g/ml, 0 if unknown
+	Piece   float64 // The weight of one (e.g. an egg or a clove) in g, 0 if unknown
+}
+
+// Converts an amount of a food into grams, using its density for volumes and
+// its piece weight for counts and named units (e.g. "clove").
+func (f Food) Grams(qty float64, unit string) (float64, error) {
+	if strings.TrimSpace(unit) == "" {
+		if f.Piece == 0 {
+			return 0, errors.New("no weight per piece")
+		}
+		return qty * f.Piece, nil
+	}
+
+	u, ok := units.Lookup(unit)
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", unit)
+	}
+	switch u.Dimension {
+	case units.Mass:
+		return units.Convert(qty, u.Name, "g")
+	case units.Volume:
+		if f.Density == 0 {
+			return 0, errors.New("no density")
+		}
+		ml, err := units.Convert(qty, u.Name, "ml")
+		return ml * f.Density, err
+	case units.Other:
+		if f.Piece == 0 {
+			return 0, errors.New("no weight per " + u.Name)
+		}
+		return qty * f.Piece, nil
+	default:
+		return 0, fmt.Errorf("%q is not an amount", unit)
+	}
+}
+
+// A nutrient table, along with aliases mapping ingredient names to its foods
+type DB struct {
+	foods   map[string]Food   // Keyed by normalized name
+	aliases map[string]string // Normalized ingredient name -> normalized food name
+}
+
+// Returns an empty nutrient table
+func NewDB() DB {
+	return DB{foods: map[string]Food{}, aliases: map[string]string{}}
+}
+
+// Returns the number of foods in the table
+func (db DB) Len() int {
+	return len(db.foods)
+}
+
+// Normalizes a food or ingredient name for lookups
+func normalize(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// The columns of a nutrient table, found by `tableColumns`
+type columns struct {
+	name, calories, protein, fat, carbs, fibre, density, piece int
+}
+
+// Finds the columns of a nutrient table by their header, -1 if missing.
+//
+// Headers are matched loosely, e.g. "Energy (kcal)", "calories" and "kcal"
+// are all calories. Energy in kJ is ignored.
+func tableColumns(header []string) columns {
+	cols := columns{-1, -1, -1, -1, -1, -1, -1, -1}
+	set := func(col *int, i int) {
+		if *col < 0 {
+			*col = i
+		}
+	}
+
+	for i, text := range header {
+		h := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, text)
+
+		switch {
+		case h == "name" || h == "description" || h == "food" || h == "fooddescription":
+			set(&cols.name, i)
+		case h == "calories" || h == "kcal" || h == "caloriestotalkcal" ||
+			(strings.HasPrefix(h, "energy") && !strings.Contains(h, "kj")):
+			set(&cols.calories, i)
+		case strings.HasPrefix(h, "protein"):
+			set(&cols.protein, i)
+		case h == "fat" || h == "fatg" || strings.HasPrefix(h, "totalfat") || strings.HasPrefix(h, "totallipid"):
+			set(&cols.fat, i)
+		case h == "carbs" || h == "carbsg" || strings.HasPrefix(h, "carbohydrate"):
+			set(&cols.carbs, i)
+		case strings.HasPrefix(h, "fiber") || strings.HasPrefix(h, "fibre") || strings.HasPrefix(h, "dietaryfib"):
+			set(&cols.fibre, i)
+		case strings.HasPrefix(h, "density"):
+			set(&cols.density, i)
+		case strings.HasPrefix(h, "piece") || strings.HasPrefix(h, "unitweight"):
+			set(&cols.piece, i)
+		}
+	}
+	return cols
+}
+
+// Reads a nutrient table in CSV format (see the package docs) into the DB.
+//
+// Foods already in the DB are overridden one value at a time, so an overrides
+// table only needs the columns it changes. Empty cells are left as they were.
+func (db DB) ReadCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	cols := tableColumns(header)
+	if cols.name < 0 {
+		return ErrNoName
+	}
+
+	for {
+		var record []string
+		if record, err = reader.Read(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+
+		cell := func(col int) string {
+			if col < 0 || col >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[col])
+		}
+		name := cell(cols.name)
+		if name == "" {
+			continue
+		}
+
+		key := normalize(name)
+		food, ok := db.foods[key]
+		if !ok {
+			food = Food{Name: name}
+		}
+		for _, field := range []struct {
+			col int
+			val *float64
+		}{
+			{cols.calories, &food.Per100g.Calories},
+			{cols.protein, &food.Per100g.Protein},
+			{cols.fat, &food.Per100g.Fat},
+			{cols.carbs, &food.Per100g.Carbs},
+			{cols.fibre, &food.Per100g.Fibre},
+			{cols.density, &food.Density},
+			{cols.piece, &food.Piece},
+		} {
+			text := cell(field.col)
+			if text == "" {
+				continue
+			}
+			val, err := strconv.ParseFloat(text, 64)
+			if err != nil || val < 0 {
+				return fmt.Errorf("Line %v: invalid %q of %v", line, header[field.col], name)
+			}
+			*field.val = val
+		}
+		db.foods[key] = food
+	}
+}
+
+// Reads the aliases of foods, one food per line followed by the ingredient
+// names which refer to it (like the synonyms of `aisle.conf`):
+//
+//	Wheat flour, white, all-purpose|flour|plain flour
+//	Egg, whole, raw|egg|eggs
+func (db DB) ReadAliases(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		names := strings.Split(line, "|")
+		if len(names) < 2 {
+			return fmt.Errorf("Line %v: %q has no aliases, expected food|alias.", n, line)
+		}
+		food := normalize(names[0])
+		for _, alias := range names[1:] {
+			if alias = normalize(alias); alias != "" {
+				db.aliases[alias] = food
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// Finds the food an ingredient name refers to, through its aliases, ignoring
+// case and plurals (e.g. "Eggs" finds "egg").
+func (db DB) Lookup(name string) (Food, bool) {
+	key := normalize(name)
+	candidates := []string{key}
+	for _, suffix := range []string{"es", "s"} {
+		if strings.HasSuffix(key, suffix) {
+			candidates = append(candidates, strings.TrimSuffix(key, suffix))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if alias, ok := db.aliases[candidate]; ok {
+			candidate = alias
+		}
+		if food, ok := db.foods[candidate]; ok {
+			return food, true
+		}
+	}
+	return Food{}, false
+}
+
+// Loads a nutrient table from `table`, then `overrides` and `aliases` (see
+// `ReadCSV` and `ReadAliases`). Overrides and aliases are skipped when their
+// files don't exist.
+func Load(table string, overrides string, aliases string) (DB, error) {
+	db := NewDB()
+
+	readFile := func(path string, read func(io.Reader) error) error {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if err = read(file); err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		return nil
+	}
+
+	if err := readFile(table, db.ReadCSV); err != nil {
+		return DB{}, err
+	}
+	if common.FileExists(overrides) {
+		if err := readFile(overrides, db.ReadCSV); err != nil {
+			return DB{}, err
+		}
+	}
+	if common.FileExists(aliases) {
+		if err := readFile(aliases, db.ReadAliases); err != nil {
+			return DB{}, err
+		}
+	}
+	return db, nil
+}
@@ -0,0 +1,161 @@
+package nutrition
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+const testTable = `fdc_id,description,Energy (kJ),Energy (kcal),Protein (g),Total lipid (fat) (g),Fatty acids (g),"Carbohydrate, by difference (g)","Fiber, total dietary (g)",density,piece
+1,"Wheat flour, white, all-purpose",1523,364,10.33,0.98,0.15,76.31,2.7,0.53,
+2,"Egg, whole, raw",598,143,12.56,9.51,3.1,0.72,0,,50
+3,"Milk, whole",255,61,3.15,3.25,1.86,4.8,0,,
+`
+
+const testOverrides = `description,calories,density
+"Milk, whole",,1.03
+Butter,717,0.91
+`
+
+const testAliases = `# food|aliases
+Wheat flour, white, all-purpose|flour|plain flour
+Egg, whole, raw|egg
+Milk, whole|milk
+`
+
+// Loads the test table, overrides and aliases
+func loadTestDB(t *testing.T) DB {
+	db := NewDB()
+	if err := db.ReadCSV(strings.NewReader(testTable)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ReadCSV(strings.NewReader(testOverrides)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ReadAliases(strings.NewReader(testAliases)); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// Tests that two floats are within rounding of each other
+func near(a float64, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestReadCSV(t *testing.T) {
+	db := loadTestDB(t)
+	if db.Len() != 4 {
+		t.Fatalf("Wrong number of foods: %v", db.Len())
+	}
+
+	flour, ok := db.Lookup("Plain  Flour")
+	want := Nutrients{Calories: 364, Protein: 10.33, Fat: 0.98, Carbs: 76.31, Fibre: 2.7}
+	if !ok || flour.Per100g != want || flour.Density != 0.53 {
+		t.Fatalf("Wrong flour\ngot: %+v\nwant: %+v", flour, want)
+	}
+
+	// Overrides only change the values they set
+	milk, _ := db.Lookup("milk")
+	if milk.Per100g.Calories != 61 || milk.Per100g.Protein != 3.15 || milk.Density != 1.03 {
+		t.Fatalf("Wrong overridden milk: %+v", milk)
+	}
+	if butter, ok := db.Lookup("butter"); !ok || butter.Per100g.Calories != 717 {
+		t.Fatalf("Override didn't add butter: %+v", butter)
+	}
+
+	// Plurals
+	if egg, ok := db.Lookup("Eggs"); !ok || egg.Piece != 50 {
+		t.Fatalf("Wrong eggs: %+v", egg)
+	}
+	if _, ok := db.Lookup("saffron"); ok {
+		t.Fatal("Found a food which isn't in the table")
+	}
+
+	for _, bad := range []string{"calories,protein\n1,2\n", "name,protein\negg,lots\n", "name,fat\negg,-1\n"} {
+		if err := NewDB().ReadCSV(strings.NewReader(bad)); err == nil {
+			t.Fatalf("Read invalid table %q", bad)
+		}
+	}
+	if err := NewDB().ReadAliases(strings.NewReader("flour\n")); err == nil {
+		t.Fatal("Read aliases without any")
+	}
+}
+
+func TestGrams(t *testing.T) {
+	food := Food{Name: "test", Density: 0.5, Piece: 20}
+	cases := []struct {
+		qty  float64
+		unit string
+		want float64
+	}{
+		{250, "g", 250},
+		{1, "kg", 1000},
+		{1, "oz", 28.35},
+		{200, "ml", 100},
+		{1, "cup", 118.29},
+		{3, "", 60},
+		{2, "cloves", 40},
+	}
+	for _, c := range cases {
+		if got, err := food.Grams(c.qty, c.unit); err != nil || !near(got, c.want) {
+			t.Fatalf("Wrong weight of %v %v\ngot: %v (%v)\nwant: %v", c.qty, c.unit, got, err, c.want)
+		}
+	}
+
+	for _, unit := range []string{"minutes", "splodge"} {
+		if _, err := food.Grams(1, unit); err == nil {
+			t.Fatalf("Weighed 1 %v", unit)
+		}
+	}
+	if _, err := (Food{}).Grams(1, "cup"); err == nil {
+		t.Fatal("Weighed a volume without a density")
+	}
+	if _, err := (Food{}).Grams(2, ""); err == nil {
+		t.Fatal("Weighed a count without a piece weight")
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	db := loadTestDB(t)
+	r := cook.ParseRecipeString("pancakes",
+		"Whisk @flour{200%g}, @eggs{2}, @milk{300%ml} and @salt with @saffron{1%pinch}.\n")
+
+	facts := db.Calculate(r.Ingredients, 4)
+	// 200 g flour, 100 g egg and 309 g milk
+	want := Nutrients{
+		Calories: 728 + 143 + 188.49,
+		Protein:  20.66 + 12.56 + 9.7335,
+		Fat:      1.96 + 9.51 + 10.0425,
+		Carbs:    152.62 + 0.72 + 14.832,
+		Fibre:    5.4,
+	}
+	got := facts.Total
+	if !near(got.Calories, want.Calories) || !near(got.Protein, want.Protein) || !near(got.Fat, want.Fat) ||
+		!near(got.Carbs, want.Carbs) || !near(got.Fibre, want.Fibre) {
+		t.Fatalf("Wrong total\ngot: %+v\nwant: %+v", got, want)
+	}
+	if facts.PerServing == nil || !near(facts.PerServing.Calories, want.Calories/4) || facts.Servings != 4 {
+		t.Fatalf("Wrong per serving: %+v", facts)
+	}
+
+	unmatched := []Unmatched{{"salt", "not in the nutrient table"}, {"saffron", "not in the nutrient table"}}
+	if len(facts.Unmatched) != len(unmatched) || facts.Unmatched[0] != unmatched[0] ||
+		facts.Unmatched[1] != unmatched[1] {
+		t.Fatalf("Wrong unmatched\ngot: %+v\nwant: %+v", facts.Unmatched, unmatched)
+	}
+
+	// Matched foods which can't be weighed are unmatched too
+	r = cook.ParseRecipeString("butter", "Melt @butter{} with @flour{2%cloves}.\n")
+	facts = db.Calculate(r.Ingredients, cook.NoQty)
+	if facts.PerServing != nil || len(facts.Unmatched) != 2 ||
+		facts.Unmatched[0].Reason != "no quantity" || facts.Unmatched[1].Reason != "no weight per clove" {
+		t.Fatalf("Wrong unweighable ingredients: %+v", facts)
+	}
+}
@@ -0,0 +1,259 @@
+// Package pantry tracks the ingredients kept at home, stored as a TOML file
+// (see `config.PantryPath`), so they can be left off shopping lists.
+package pantry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+	"github.com/BurntSushi/toml"
+)
+
+// The format of expiry dates
+const DateFormat = "2006-01-02"
+
+// Quantities this close to zero are treated as used up, to absorb rounding
+// from unit conversion.
+const epsilon = 1e-9
+
+var ErrItemNotFound = errors.New("Pantry item not found.")
+
+// An ingredient in stock. Items without a quantity are in stock in an unknown
+// amount, and cover any amount needed.
+type Item struct {
+	Name    string  `toml:"name" json:"name"`
+	Qty     float64 `toml:"qty,omitempty" json:"qty,omitempty"`
+	Unit    string  `toml:"unit,omitempty" json:"unit,omitempty"`
+	Expires string  `toml:"expires,omitempty" json:"expires,omitempty"` // e.g. 2026-10-31
+}
+
+// Formats the amount of an item, e.g. "1.5 kg", empty if unknown
+func (item Item) AmountText() string {
+	if item.Qty == cook.NoQty {
+		return ""
+	}
+	return strings.TrimSpace(cook.FormatQty(item.Qty) + " " + item.Unit)
+}
+
+// Returns whether two items are the same stock, i.e. share a name (ignoring
+// case) and an amount in compatible units.
+func (item Item) matches(other Item) bool {
+	if !strings.EqualFold(item.Name, other.Name) {
+		return false
+	}
+	if item.Qty == cook.NoQty || other.Qty == cook.NoQty {
+		return item.Qty == other.Qty
+	}
+	return units.Compatible(item.Unit, other.Unit)
+}
+
+// The ingredients in stock
+type Pantry struct {
+	Items []Item `toml:"items" json:"items"`
+}
+
+// Adds stock to the pantry, summing it into an existing item of the same name
+// and a compatible unit. The earlier expiry date is kept when summing.
+//
+// Returns an error if the expiry date isn't formatted as `DateFormat`.
+func (p *Pantry) Add(item Item) error {
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" {
+		return errors.New("Pantry items need a name.")
+	}
+	if item.Expires != "" {
+		if _, err := time.Parse(DateFormat, item.Expires); err != nil {
+			return errors.New("Invalid expiry date " + item.Expires + ", expected e.g. 2026-10-31")
+		}
+	}
+
+	for i, existing := range p.Items {
+		if !existing.matches(item) {
+			continue
+		}
+		if item.Qty != cook.NoQty {
+			qty, _ := units.Convert(item.Qty, item.Unit, existing.Unit)
+			p.Items[i].Qty += qty
+		}
+		if existing.Expires == "" || (item.Expires != "" && item.Expires < existing.Expires) {
+			p.Items[i].Expires = item.Expires
+		}
+		return nil
+	}
+
+	p.Items = append(p.Items, item)
+	return nil
+}
+
+// Removes stock from the pantry. Items without a quantity remove every item of
+// that name, otherwise the quantity is taken from items of compatible units
+// (removing those which run out).
+//
+// Returns ErrItemNotFound if nothing of that name is in stock.
+func (p *Pantry) Remove(item Item) error {
+	found := false
+	remaining := item.Qty
+	kept := make([]Item, 0, len(p.Items))
+	for _, existing := range p.Items {
+		if !strings.EqualFold(existing.Name, strings.TrimSpace(item.Name)) {
+			kept = append(kept, existing)
+			continue
+		}
+		found = true
+
+		if item.Qty == cook.NoQty {
+			continue
+		}
+		if existing.Qty == cook.NoQty || remaining <= epsilon || !units.Compatible(item.Unit, existing.Unit) {
+			kept = append(kept, existing)
+			continue
+		}
+
+		have, _ := units.Convert(existing.Qty, existing.Unit, item.Unit)
+		if have-remaining > epsilon {
+			existing.Qty, _ = units.Convert(have-remaining, item.Unit, existing.Unit)
+			kept = append(kept, existing)
+		}
+		remaining -= have
+	}
+
+	if !found {
+		return ErrItemNotFound
+	}
+	if remaining == item.Qty && item.Qty != cook.NoQty {
+		return fmt.Errorf("No %v is in stock in units compatible with %q.", item.Name, item.Unit)
+	}
+	p.Items = kept
+	return nil
+}
+
+// Returns the items which expire within `days` of `now` (including those which
+// already have), soonest first.
+func (p Pantry) Expiring(now time.Time, days int) []Item {
+	cutoff := now.AddDate(0, 0, days).Format(DateFormat)
+
+	items := make([]Item, 0)
+	for _, item := range p.Items {
+		if item.Expires != "" && item.Expires <= cutoff {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Expires < items[j].Expires
+	})
+	return items
+}
+
+// Removes what's in stock from shopping list items, returning the items which
+// still need buying. Names are matched ignoring case, and through synonyms in
+// `aisles`. Quantities are subtracted across compatible units, and items in
+// stock without a quantity are assumed to cover any amount.
+func (p Pantry) Subtract(items []shopping.Item, aisles shopping.Aisles) []shopping.Item {
+	// Stock left to take from, by canonical name
+	stock := map[string][]Item{}
+	for _, item := range p.Items {
+		_, name := aisles.Lookup(item.Name)
+		key := strings.ToLower(name)
+		stock[key] = append(stock[key], item)
+	}
+
+	needed := make([]shopping.Item, 0, len(items))
+	for _, item := range items {
+		have := stock[strings.ToLower(item.Name)]
+		if len(have) == 0 {
+			needed = append(needed, item)
+			continue
+		}
+
+		amounts := make([]shopping.Amount, 0, len(item.Amounts))
+		for _, amount := range item.Amounts {
+			if amount, ok := take(have, amount); !ok {
+				amounts = append(amounts, amount)
+			}
+		}
+		if len(amounts) > 0 {
+			item.Amounts = amounts
+			needed = append(needed, item)
+		}
+	}
+	return needed
+}
+
+// Takes an amount from stock (updating it), returning what's left to buy and
+// whether the amount was covered entirely.
+func take(stock []Item, amount shopping.Amount) (shopping.Amount, bool) {
+	for i, item := range stock {
+		if item.Qty == cook.NoQty {
+			return amount, true
+		}
+		if amount.Qty == cook.NoQty {
+			// A written amount (e.g. "a pinch") is covered by any stock
+			if item.Qty > epsilon {
+				return amount, true
+			}
+			continue
+		}
+		if item.Qty <= epsilon || !units.Compatible(item.Unit, amount.Unit) {
+			continue
+		}
+
+		have, _ := units.Convert(item.Qty, item.Unit, amount.Unit)
+		if have-amount.Qty > -epsilon {
+			stock[i].Qty, _ = units.Convert(have-amount.Qty, amount.Unit, item.Unit)
+			return amount, true
+		}
+		stock[i].Qty = 0
+		amount.Qty -= have
+	}
+	return amount, false
+}
+
+// Reads the pantry at `path`. A missing file is an empty pantry.
+func Load(path string) (Pantry, error) {
+	pantry := Pantry{Items: []Item{}}
+	if !common.FileExists(path) {
+		return pantry, nil
+	}
+
+	if _, err := toml.DecodeFile(path, &pantry); err != nil {
+		return Pantry{}, err
+	}
+	if pantry.Items == nil {
+		pantry.Items = []Item{}
+	}
+	return pantry, nil
+}
+
+// Writes the pantry to `path`, through a temporary file so readers never see
+// a partial pantry.
+func Save(path string, pantry Pantry) error {
+	var err error
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	var file *os.File
+	if file, err = os.CreateTemp(dir, ".pantry-*.tmp"); err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err = toml.NewEncoder(file).Encode(pantry); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,160 @@
+package pantry
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestAddRemove(t *testing.T) {
+	p := Pantry{}
+	testAmounts := func(want ...string) {
+		got := make([]string, 0, len(p.Items))
+		for _, item := range p.Items {
+			got = append(got, strings.TrimSpace(item.Name+" "+item.AmountText()))
+		}
+		if strings.Join(got, ", ") != strings.Join(want, ", ") {
+			t.Fatalf("Wrong items\ngot: %v\nwant: %v.", got, want)
+		}
+	}
+
+	for _, item := range []Item{
+		{Name: "flour", Qty: 1, Unit: "kg", Expires: "2026-12-01"},
+		{Name: "Flour", Qty: 500, Unit: "g", Expires: "2026-11-01"},
+		{Name: "flour", Qty: 2, Unit: "cups"},
+		{Name: "salt"},
+		{Name: "salt"},
+	} {
+		if err := p.Add(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	testAmounts("flour 1.5 kg", "flour 2 cups", "salt")
+	if p.Items[0].Expires != "2026-11-01" {
+		t.Fatalf("Kept the wrong expiry\ngot: %v\nwant: %v.", p.Items[0].Expires, "2026-11-01")
+	}
+	if err := p.Add(Item{Name: "milk", Expires: "tomorrow"}); err == nil {
+		t.Fatalf("Added an item with an invalid expiry date")
+	}
+
+	if err := p.Remove(Item{Name: "flour", Qty: 1000, Unit: "g"}); err != nil {
+		t.Fatal(err)
+	}
+	testAmounts("flour 0.5 kg", "flour 2 cups", "salt")
+	if err := p.Remove(Item{Name: "flour", Qty: 1, Unit: "sacks"}); err == nil {
+		t.Fatalf("Removed flour in incompatible units")
+	}
+	if err := p.Remove(Item{Name: "salt"}); err != nil {
+		t.Fatal(err)
+	}
+	testAmounts("flour 0.5 kg", "flour 2 cups")
+	if err := p.Remove(Item{Name: "salt"}); err != ErrItemNotFound {
+		t.Fatalf("Wrong error removing a missing item\ngot: %v\nwant: %v.", err, ErrItemNotFound)
+	}
+}
+
+func TestExpiring(t *testing.T) {
+	p := Pantry{Items: []Item{
+		{Name: "milk", Expires: "2026-10-20"},
+		{Name: "yogurt", Expires: "2026-10-10"},
+		{Name: "rice"},
+		{Name: "cheese", Expires: "2026-11-30"},
+	}}
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	items := p.Expiring(now, 7)
+	if len(items) != 2 || items[0].Name != "yogurt" || items[1].Name != "milk" {
+		t.Fatalf("Wrong expiring items\ngot: %+v\nwant: yogurt, milk.", items)
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	r := cook.ParseRecipeString("", "@flour{500%g} @flour{1%cup} @milk{1%l} @eggs{6} "+
+		"@salt{a pinch} @pepper @scallions{2}")
+	aisles, err := shopping.ParseAisles("[produce]\nscallions|green onions\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := shopping.Combine(r.Ingredients, "", aisles)
+
+	p := Pantry{Items: []Item{
+		{Name: "flour", Qty: 2, Unit: "kg"},
+		{Name: "milk", Qty: 250, Unit: "ml"},
+		{Name: "eggs", Qty: 2},
+		{Name: "eggs", Qty: 1},
+		{Name: "salt"},
+		{Name: "green onions", Qty: 5},
+	}}
+	got := map[string]string{}
+	for _, item := range p.Subtract(items, aisles) {
+		got[item.Name] = item.AmountText()
+	}
+	want := map[string]string{"flour": "1 cup", "milk": "0.75 l", "eggs": "3", "pepper": ""}
+	if len(got) != len(want) {
+		t.Fatalf("Wrong items left\ngot: %v\nwant: %v.", got, want)
+	}
+	for name, amount := range want {
+		if got[name] != amount {
+			t.Fatalf("Wrong amount of %v left\ngot: %q\nwant: %q.", name, got[name], amount)
+		}
+	}
+	if p.Items[0].Qty != 2 {
+		t.Fatalf("Subtracting changed the pantry: %+v", p.Items[0])
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "pantry.toml")
+	if p, err := Load(path); err != nil || len(p.Items) != 0 {
+		t.Fatalf("Missing pantry isn't empty: %+v (%v)", p, err)
+	}
+
+	want := Pantry{Items: []Item{{Name: "flour", Qty: 1.5, Unit: "kg", Expires: "2026-12-01"}}}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil || len(got.Items) != 1 || got.Items[0] != want.Items[0] {
+		t.Fatalf("Failed to load saved pantry\ngot: %+v (%v)\nwant: %+v.", got, err, want)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	p := Pantry{Items: []Item{
+		{Name: "pasta", Qty: 1, Unit: "kg"},
+		{Name: "garlic"},
+		{Name: "eggs", Qty: 2},
+	}}
+	parse := func(source string) []cook.Ingredient {
+		return cook.ParseRecipeString("", source).Ingredients
+	}
+
+	suggestions := []Suggestion{
+		p.Suggest("omelette", parse("@eggs{3} @butter{1%tbsp}"), shopping.Aisles{}),
+		p.Suggest("aglio", parse("@pasta{500%g} @garlic{3%cloves} @chili{1}"), shopping.Aisles{}),
+		p.Suggest("toast", parse("@bread{2%slices} @garlic{}"), shopping.Aisles{}),
+	}
+	RankSuggestions(suggestions)
+
+	got := make([]string, 0, len(suggestions))
+	for _, s := range suggestions {
+		got = append(got, fmt.Sprintf("%v %v/%v missing %v", s.Recipe, s.InStock, s.Total, s.Missing))
+	}
+	want := []string{
+		"aglio 2/3 missing [chili]",
+		"toast 1/2 missing [bread]",
+		"omelette 0/2 missing [butter eggs]",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Wrong suggestions\ngot: %v\nwant: %v.", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package pantry
+
+import (
+	"sort"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/shopping"
+)
+
+// A recipe which could be cooked from the pantry, and what it's missing
+type Suggestion struct {
+	Recipe  string   `json:"recipe"`
+	Total   int      `json:"total"`   // Required ingredients
+	InStock int      `json:"inStock"` // Required ingredients in stock
+	Missing []string `json:"missing"`
+	Minutes int      `json:"minutes,omitempty"` // Total time, 0 if unknown
+}
+
+// Checks which of a recipe's (required) ingredients are in stock, in the
+// amounts needed. Names are matched as in `Subtract`.
+func (p Pantry) Suggest(recipe string, ingredients []cook.Ingredient, aisles shopping.Aisles) Suggestion {
+	items := shopping.Combine(ingredients, "", aisles)
+	needed := p.Subtract(items, aisles)
+
+	suggestion := Suggestion{
+		Recipe:  recipe,
+		Total:   len(items),
+		InStock: len(items) - len(needed),
+		Missing: make([]string, 0, len(needed)),
+	}
+	for _, item := range needed {
+		suggestion.Missing = append(suggestion.Missing, item.Name)
+	}
+	return suggestion
+}
+
+// Sorts suggestions by the fewest ingredients missing, then the most in stock,
+// then by recipe name.
+func RankSuggestions(suggestions []Suggestion) {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if len(a.Missing) != len(b.Missing) {
+			return len(a.Missing) < len(b.Missing)
+		}
+		if a.InStock != b.InStock {
+			return a.InStock > b.InStock
+		}
+		return a.Recipe < b.Recipe
+	})
+}
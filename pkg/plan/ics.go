@@ -0,0 +1,159 @@
+package plan
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// The time each meal starts, others are all-day events unless configured
+var DefaultMealTimes = map[string]string{
+	"breakfast": "08:00",
+	"brunch":    "10:30",
+	"lunch":     "12:00",
+	"snack":     "15:00",
+	"dinner":    "18:00",
+	"dessert":   "19:30",
+}
+
+// How long meals last if not configured
+const DefaultMealDuration = time.Hour
+
+// A planned meal, as an event of the calendar feed
+type Event struct {
+	Meal        Meal
+	Title       string   // The recipe's title, e.g. "Lasagna"
+	Ingredients []string // e.g. "500 g pasta"
+	URL         string   // The recipe's page, left out if empty
+}
+
+// Options of `WriteICS`
+type ICSOptions struct {
+	MealTimes map[string]string // Overrides `DefaultMealTimes`, e.g. dinner = "18:30"
+	Duration  time.Duration     // `DefaultMealDuration` if 0
+	Stamp     time.Time         // When the feed was generated
+}
+
+// Returns the start time of a meal as hours and minutes, false if it has none
+func (opts ICSOptions) mealTime(meal string) (int, int, bool) {
+	text, ok := opts.MealTimes[meal]
+	if !ok {
+		text, ok = DefaultMealTimes[meal]
+	}
+	if !ok {
+		return 0, 0, false
+	}
+
+	t, err := time.Parse("15:04", strings.TrimSpace(text))
+	if err != nil {
+		return 0, 0, false
+	}
+	return t.Hour(), t.Minute(), true
+}
+
+// Escapes text for an iCalendar property value
+func icsEscape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// Writes a content line, folded every 75 octets (without splitting runes)
+func writeICSLine(w *bufio.Writer, line string) {
+	for limit := 75; len(line) > limit; limit = 74 {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	w.WriteString(line + "\r\n")
+}
+
+// Returns a stable identifier for the event of a meal
+func eventUID(meal Meal) string {
+	sum := sha1.Sum([]byte(meal.Date + "\x00" + meal.Meal + "\x00" + meal.Recipe))
+	return hex.EncodeToString(sum[:]) + "@go-cook"
+}
+
+// Writes meals as an RFC 5545 iCalendar feed, one event per meal. Events start
+// at the time of their meal (in the calendar's local time), meals without a
+// time are all-day events. Descriptions list the ingredients needed and link
+// back to the recipe.
+func WriteICS(w io.Writer, events []Event, opts ICSOptions) error {
+	duration := opts.Duration
+	if duration <= 0 {
+		duration = DefaultMealDuration
+	}
+	stamp := opts.Stamp.UTC().Format("20060102T150405Z")
+
+	buf := bufio.NewWriter(w)
+	writeICSLine(buf, "BEGIN:VCALENDAR")
+	writeICSLine(buf, "VERSION:2.0")
+	writeICSLine(buf, "PRODID:-//go-cook//Meal Plan//EN")
+	writeICSLine(buf, "CALSCALE:GREGORIAN")
+	writeICSLine(buf, "X-WR-CALNAME:Meal Plan")
+
+	for _, event := range events {
+		date, err := time.Parse(DateFormat, event.Meal.Date)
+		if err != nil {
+			return fmt.Errorf("Invalid date %v of %v", event.Meal.Date, event.Meal.Recipe)
+		}
+
+		writeICSLine(buf, "BEGIN:VEVENT")
+		writeICSLine(buf, "UID:"+eventUID(event.Meal))
+		writeICSLine(buf, "DTSTAMP:"+stamp)
+		if hour, minute, ok := opts.mealTime(event.Meal.Meal); ok {
+			start := date.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+			writeICSLine(buf, "DTSTART:"+start.Format("20060102T150405"))
+			writeICSLine(buf, "DTEND:"+start.Add(duration).Format("20060102T150405"))
+		} else {
+			writeICSLine(buf, "DTSTART;VALUE=DATE:"+date.Format("20060102"))
+			writeICSLine(buf, "DTEND;VALUE=DATE:"+date.AddDate(0, 0, 1).Format("20060102"))
+		}
+
+		title := event.Title
+		if title == "" {
+			title = event.Meal.Recipe
+		}
+		meal := event.Meal.Meal
+		if meal != "" {
+			meal = strings.ToUpper(meal[:1]) + meal[1:]
+		}
+		writeICSLine(buf, "SUMMARY:"+icsEscape(meal+": "+title))
+
+		var desc strings.Builder
+		if event.Meal.Servings > 0 {
+			desc.WriteString("Serves " + cook.FormatQty(event.Meal.Servings) + "\n\n")
+		}
+		if len(event.Ingredients) > 0 {
+			desc.WriteString("Ingredients:\n")
+			for _, ingr := range event.Ingredients {
+				desc.WriteString("- " + ingr + "\n")
+			}
+		}
+		if event.URL != "" {
+			desc.WriteString("\n" + event.URL)
+			writeICSLine(buf, "URL:"+event.URL)
+		}
+		if text := strings.TrimSpace(desc.String()); text != "" {
+			writeICSLine(buf, "DESCRIPTION:"+icsEscape(text))
+		}
+		writeICSLine(buf, "END:VEVENT")
+	}
+
+	writeICSLine(buf, "END:VCALENDAR")
+	return buf.Flush()
+}
@@ -0,0 +1,60 @@
+package plan
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestWriteICS(t *testing.T) {
+	events := []Event{
+		{
+			Meal:        Meal{Date: "2026-10-20", Meal: "dinner", Recipe: "mains/lasagna", Servings: 4},
+			Title:       "Lasagna, Classic",
+			Ingredients: []string{"500 g pasta sheets", "1 jar passata; chunky", "mozzarella"},
+			URL:         "http://localhost:6969/?recipe=mains%2Flasagna",
+		},
+		{Meal: Meal{Date: "2026-10-21", Meal: "picnic", Recipe: "sandwiches"}},
+	}
+	opts := ICSOptions{
+		MealTimes: map[string]string{"dinner": "18:30"},
+		Duration:  90 * time.Minute,
+		Stamp:     time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, events, opts); err != nil {
+		t.Fatal(err)
+	}
+	ics := buf.String()
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("Line was not folded: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"DTSTAMP:20261018T090000Z\r\n",
+		"DTSTART:20261020T183000\r\nDTEND:20261020T200000\r\n",
+		"SUMMARY:Dinner: Lasagna\\, Classic\r\n",
+		"DESCRIPTION:Serves 4\\n\\nIngredients:\\n- 500 g pasta sheets\\n- 1 jar passata\\; chunky\\n" +
+			"- mozzarella\\n\\nhttp://localhost:6969/?recipe=mains%2Flasagna\r\n",
+		"DTSTART;VALUE=DATE:20261021\r\nDTEND;VALUE=DATE:20261022\r\n",
+		"SUMMARY:Picnic: sandwiches\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Fatalf("Calendar is missing %q\ngot:\n%v", want, unfolded)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 2 || strings.Count(ics, "UID:") != 2 {
+		t.Fatalf("Wrong events:\n%v", ics)
+	}
+}
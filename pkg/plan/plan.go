@@ -0,0 +1,247 @@
+// Package plan maps dates and meals to the recipes planned for them, stored as
+// a TOML file (see `config.PlanPath`):
+//
+//	[[meals]]
+//	  date = "2026-10-19"
+//	  meal = "dinner"
+//	  recipe = "mains/lasagna"
+//	  servings = 4.0
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"github.com/BurntSushi/toml"
+)
+
+// The format of planned dates
+const DateFormat = "2006-01-02"
+
+// The meal recipes are planned for if none is given
+const DefaultMeal = "dinner"
+
+// The order meals are listed in within a day, others follow alphabetically
+var mealOrder = []string{"breakfast", "brunch", "lunch", "snack", "dinner", "dessert"}
+
+var ErrMealNotFound = errors.New("No planned meal matches.")
+
+// A recipe planned for a meal
+type Meal struct {
+	Date     string  `toml:"date" json:"date"` // e.g. 2026-10-19
+	Meal     string  `toml:"meal" json:"meal"` // e.g. dinner
+	Recipe   string  `toml:"recipe" json:"recipe"`
+	Servings float64 `toml:"servings,omitempty" json:"servings,omitempty"` // 0 as written
+}
+
+// Returns the recipe of a meal as a shopping list argument, e.g. "lasagna:4"
+// (see `shopping.ParseRecipeArg`).
+func (m Meal) RecipeArg() string {
+	if m.Servings == cook.NoQty {
+		return m.Recipe
+	}
+	return m.Recipe + ":" + strconv.FormatFloat(m.Servings, 'f', -1, 64)
+}
+
+// Returns the position of a meal within a day
+func mealRank(meal string) int {
+	for i, name := range mealOrder {
+		if name == meal {
+			return i
+		}
+	}
+	return len(mealOrder)
+}
+
+// Every planned meal
+type Plan struct {
+	Meals []Meal `toml:"meals" json:"meals"`
+}
+
+// Sorts meals by date, then by meal (see `mealOrder`), then by recipe
+func (p *Plan) sort() {
+	sort.SliceStable(p.Meals, func(i, j int) bool {
+		a, b := p.Meals[i], p.Meals[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if rankA, rankB := mealRank(a.Meal), mealRank(b.Meal); rankA != rankB {
+			return rankA < rankB
+		}
+		if a.Meal != b.Meal {
+			return a.Meal < b.Meal
+		}
+		return a.Recipe < b.Recipe
+	})
+}
+
+// Plans a recipe for a meal. Planning a recipe already planned for that meal
+// updates its servings. Meals default to `DefaultMeal`.
+//
+// Returns an error if the date isn't formatted as `DateFormat`.
+func (p *Plan) Add(meal Meal) error {
+	if _, err := time.Parse(DateFormat, meal.Date); err != nil {
+		return fmt.Errorf("Invalid date %v, expected e.g. 2026-10-19", meal.Date)
+	}
+	meal.Recipe = strings.TrimSuffix(strings.TrimSpace(meal.Recipe), ".cook")
+	if meal.Recipe == "" {
+		return errors.New("Planned meals need a recipe.")
+	}
+	meal.Meal = strings.ToLower(strings.TrimSpace(meal.Meal))
+	if meal.Meal == "" {
+		meal.Meal = DefaultMeal
+	}
+
+	for i, existing := range p.Meals {
+		if existing.Date == meal.Date && existing.Meal == meal.Meal && existing.Recipe == meal.Recipe {
+			p.Meals[i].Servings = meal.Servings
+			return nil
+		}
+	}
+	p.Meals = append(p.Meals, meal)
+	p.sort()
+	return nil
+}
+
+// Removes planned meals on `date`. Only those of `meal` and `recipe` are
+// removed, unless they are empty.
+//
+// Returns ErrMealNotFound if nothing was removed.
+func (p *Plan) Remove(date string, meal string, recipe string) error {
+	meal = strings.ToLower(strings.TrimSpace(meal))
+	recipe = strings.TrimSuffix(strings.TrimSpace(recipe), ".cook")
+
+	kept := make([]Meal, 0, len(p.Meals))
+	for _, existing := range p.Meals {
+		if existing.Date == date && (meal == "" || existing.Meal == meal) &&
+			(recipe == "" || existing.Recipe == recipe) {
+			continue
+		}
+		kept = append(kept, existing)
+	}
+
+	if len(kept) == len(p.Meals) {
+		return ErrMealNotFound
+	}
+	p.Meals = kept
+	return nil
+}
+
+// Returns the meals planned from `from` up to (not including) `to`.
+func (p Plan) Between(from time.Time, to time.Time) []Meal {
+	start, end := from.Format(DateFormat), to.Format(DateFormat)
+
+	meals := make([]Meal, 0)
+	for _, meal := range p.Meals {
+		if meal.Date >= start && meal.Date < end {
+			meals = append(meals, meal)
+		}
+	}
+	return meals
+}
+
+// Reads a date to plan for, either as `DateFormat` or relative to `now`:
+// "today", "tomorrow" or a weekday (e.g. "friday", the next one from today).
+// Returns the date formatted as `DateFormat`.
+func ParseDate(text string, now time.Time) (string, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	switch text {
+	case "today":
+		return now.Format(DateFormat), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format(DateFormat), nil
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if text == name || (len(text) >= 3 && strings.HasPrefix(name, text)) {
+			days := (int(day) - int(now.Weekday()) + 7) % 7
+			return now.AddDate(0, 0, days).Format(DateFormat), nil
+		}
+	}
+
+	if _, err := time.Parse(DateFormat, text); err != nil {
+		return "", fmt.Errorf("Invalid date %q, expected e.g. 2026-10-19, today or friday", text)
+	}
+	return text, nil
+}
+
+// Matches ISO 8601 weeks, e.g. 2026-W43
+var weekRegex = regexp.MustCompile(`^(\d{4})-?W(\d{1,2})$`)
+
+// Returns the Monday which starts an ISO 8601 week, e.g. "2026-W43".
+func ParseWeek(week string) (time.Time, error) {
+	match := weekRegex.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(week)))
+	if match == nil {
+		return time.Time{}, fmt.Errorf("Invalid week %q, expected e.g. 2026-W43", week)
+	}
+	year, _ := strconv.Atoi(match[1])
+	num, _ := strconv.Atoi(match[2])
+
+	// The 4th of January is always in the first week
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	offset := (int(jan4.Weekday()) + 6) % 7 // Days since Monday
+	monday := jan4.AddDate(0, 0, -offset+7*(num-1))
+
+	if y, w := monday.ISOWeek(); y != year || w != num {
+		return time.Time{}, fmt.Errorf("Invalid week %q, %v has no week %v", week, year, num)
+	}
+	return monday, nil
+}
+
+// Formats the ISO 8601 week of a date, e.g. "2026-W43"
+func FormatWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Reads the plan at `path`. A missing file is an empty plan.
+func Load(path string) (Plan, error) {
+	plan := Plan{Meals: []Meal{}}
+	if !common.FileExists(path) {
+		return plan, nil
+	}
+
+	if _, err := toml.DecodeFile(path, &plan); err != nil {
+		return Plan{}, err
+	}
+	if plan.Meals == nil {
+		plan.Meals = []Meal{}
+	}
+	plan.sort()
+	return plan, nil
+}
+
+// Writes the plan to `path`, through a temporary file so readers never see a
+// partial plan.
+func Save(path string, plan Plan) error {
+	var err error
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	var file *os.File
+	if file, err = os.CreateTemp(dir, ".plan-*.tmp"); err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err = toml.NewEncoder(file).Encode(plan); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,111 @@
+package plan
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestParseWeek(t *testing.T) {
+	testWeek := func(week string, want string) {
+		monday, err := ParseWeek(week)
+		if err != nil || monday.Format(DateFormat) != want {
+			t.Fatalf("Wrong start of %v\ngot: %v (%v)\nwant: %v.", week, monday, err, want)
+		}
+		if got := FormatWeek(monday.AddDate(0, 0, 6)); got != strings.ToUpper(week) {
+			t.Fatalf("Wrong week of %v\ngot: %v\nwant: %v.", monday, got, strings.ToUpper(week))
+		}
+	}
+
+	testWeek("2026-W43", "2026-10-19")
+	testWeek("2026-W01", "2025-12-29")
+	testWeek("2020-W53", "2020-12-28")
+	testWeek("2027-w01", "2027-01-04")
+	for _, invalid := range []string{"2026-W54", "2026-W00", "2026-43", "next week"} {
+		if _, err := ParseWeek(invalid); err == nil {
+			t.Fatalf("Parsed invalid week %q", invalid)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC) // A Sunday
+	testDate := func(text string, want string) {
+		if got, err := ParseDate(text, now); err != nil || got != want {
+			t.Fatalf("Wrong date for %q\ngot: %v (%v)\nwant: %v.", text, got, err, want)
+		}
+	}
+
+	testDate("2026-11-02", "2026-11-02")
+	testDate("today", "2026-10-18")
+	testDate("Tomorrow", "2026-10-19")
+	testDate("friday", "2026-10-23")
+	testDate("sun", "2026-10-18")
+	if _, err := ParseDate("someday", now); err == nil {
+		t.Fatalf("Parsed an invalid date")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	p := Plan{}
+	testMeals := func(meals []Meal, want ...string) {
+		got := make([]string, 0, len(meals))
+		for _, meal := range meals {
+			got = append(got, meal.Date+" "+meal.Meal+" "+meal.RecipeArg())
+		}
+		if strings.Join(got, ", ") != strings.Join(want, ", ") {
+			t.Fatalf("Wrong meals\ngot: %v\nwant: %v.", got, want)
+		}
+	}
+
+	for _, meal := range []Meal{
+		{Date: "2026-10-20", Recipe: "lasagna.cook", Servings: 4},
+		{Date: "2026-10-19", Meal: "Lunch", Recipe: "soup"},
+		{Date: "2026-10-19", Meal: "breakfast", Recipe: "pancakes", Servings: 2},
+		{Date: "2026-10-26", Recipe: "curry"},
+		{Date: "2026-10-20", Recipe: "lasagna", Servings: 6},
+	} {
+		if err := p.Add(meal); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.Add(Meal{Date: "20/10/2026", Recipe: "soup"}); err == nil {
+		t.Fatalf("Planned a meal with an invalid date")
+	}
+	testMeals(p.Meals,
+		"2026-10-19 breakfast pancakes:2",
+		"2026-10-19 lunch soup",
+		"2026-10-20 dinner lasagna:6",
+		"2026-10-26 dinner curry")
+
+	monday, _ := ParseWeek("2026-W43")
+	testMeals(p.Between(monday, monday.AddDate(0, 0, 7)),
+		"2026-10-19 breakfast pancakes:2",
+		"2026-10-19 lunch soup",
+		"2026-10-20 dinner lasagna:6")
+
+	if err := p.Remove("2026-10-19", "lunch", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Remove("2026-10-20", "", "curry"); err != ErrMealNotFound {
+		t.Fatalf("Wrong error removing a missing meal\ngot: %v\nwant: %v.", err, ErrMealNotFound)
+	}
+
+	path := filepath.Join(t.TempDir(), "plan.toml")
+	if err := Save(path, p); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testMeals(loaded.Meals,
+		"2026-10-19 breakfast pancakes:2",
+		"2026-10-20 dinner lasagna:6",
+		"2026-10-26 dinner curry")
+}
@@ -0,0 +1,176 @@
+package recipe
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/nutrition"
+)
+
+// A recipe in the JSON shape of the cooklang spec's canonical tests.
+//
+// `steps` and `metadata` follow the `result` of the canonical tests at spec
+// revision fa9bc51 (the revision the parser's `canonical_test.go` is
+// generated from). Steps are lists of typed items, e.g.
+//
+//	{"type": "ingredient", "name": "flour", "quantity": 2, "units": "cups"}
+//
+// The top-level `ingredients` and `cookware` lists are an addition, holding
+// the same items as the steps, as is the `nutrition` (when calculated).
+type CanonicalRecipe struct {
+	Ingredients []CanonicalItem   `json:"ingredients"`
+	Cookware    []CanonicalItem   `json:"cookware"`
+	Steps       [][]CanonicalItem `json:"steps"`
+	Metadata    CanonicalMetadata `json:"metadata"`
+	Nutrition   *nutrition.Facts  `json:"nutrition,omitempty"`
+}
+
+// A single item of a step (or of the ingredient/cookware lists).
+//
+// Text has only a `value`, every other type has a `name`, `quantity` and
+// `units`. Quantities are numbers when they can be parsed, otherwise strings.
+type CanonicalItem struct {
+	Type     string
+	Value    string
+	Name     string
+	Quantity any
+	Units    string
+}
+
+func (item CanonicalItem) MarshalJSON() ([]byte, error) {
+	if item.Type == "text" {
+		return json.Marshal(struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		}{item.Type, item.Value})
+	}
+
+	return json.Marshal(struct {
+		Type     string `json:"type"`
+		Name     string `json:"name"`
+		Quantity any    `json:"quantity"`
+		Units    string `json:"units"`
+	}{item.Type, item.Name, item.Quantity, item.Units})
+}
+
+// Metadata as a flat object of strings, with its keys kept in order.
+// Repeated keys have their values joined, e.g. "tags": "vegan, quick"
+type CanonicalMetadata cook.Metadata
+
+func (m CanonicalMetadata) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, entry := range m {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(strings.Join(entry.Values, ", "))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Returns the quantity of a component as the canonical tests write it: a number if it
+// was parsed, otherwise the written text or `fallback` if there is none.
+func canonicalQuantity(c cook.Component, fallback any) any {
+	if c.QtyVal != cook.NoQty {
+		return c.QtyVal
+	}
+	if c.Qty == "" {
+		return fallback
+	}
+	return c.Qty
+}
+
+// Converts a chunk into a canonical item. Temperatures aren't part of the spec,
+// so they are written as text.
+func toCanonicalItem(chunk cook.Chunk) CanonicalItem {
+	switch chunk := chunk.(type) {
+	case cook.Ingredient:
+		return CanonicalItem{
+			Type:     "ingredient",
+			Name:     chunk.Name,
+			Quantity: canonicalQuantity(cook.Component(chunk), "some"),
+			Units:    chunk.Unit,
+		}
+	case cook.Cookware:
+		return CanonicalItem{
+			Type:     "cookware",
+			Name:     chunk.Name,
+			Quantity: canonicalQuantity(cook.Component(chunk), 1),
+			Units:    chunk.Unit,
+		}
+	case cook.Timer:
+		return CanonicalItem{
+			Type:     "timer",
+			Name:     chunk.Name,
+			Quantity: canonicalQuantity(cook.Component(chunk), ""),
+			Units:    chunk.Unit,
+		}
+	case cook.Temperature:
+		return CanonicalItem{Type: "text", Value: chunk.Raw}
+	default:
+		return CanonicalItem{Type: "text", Value: chunk.ToString()}
+	}
+}
+
+// Converts a recipe into the canonical JSON shape, see `CanonicalRecipe`.
+//
+// Adjacent text (e.g. around a temperature) is merged into a single item.
+func ToCanonicalRecipe(r *cook.Recipe) CanonicalRecipe {
+	out := CanonicalRecipe{
+		Ingredients: make([]CanonicalItem, 0, len(r.Ingredients)),
+		Cookware:    make([]CanonicalItem, 0, len(r.Cookware)),
+		Steps:       make([][]CanonicalItem, 0, len(r.Steps)),
+		Metadata:    CanonicalMetadata(r.Metadata),
+	}
+	if out.Metadata == nil {
+		out.Metadata = CanonicalMetadata{}
+	}
+
+	for _, ingr := range r.Ingredients {
+		out.Ingredients = append(out.Ingredients, toCanonicalItem(ingr))
+	}
+	for _, cookware := range r.Cookware {
+		out.Cookware = append(out.Cookware, toCanonicalItem(cookware))
+	}
+
+	for _, step := range r.Steps {
+		items := make([]CanonicalItem, 0, len(step))
+		for _, chunk := range step {
+			item := toCanonicalItem(chunk)
+			if last := len(items) - 1; item.Type == "text" && last >= 0 && items[last].Type == "text" {
+				items[last].Value += item.Value
+				continue
+			}
+			items = append(items, item)
+		}
+		out.Steps = append(out.Steps, items)
+	}
+
+	return out
+}
+
+// Renders a recipe as JSON in the canonical shape, see `CanonicalRecipe`.
+type CanonicalRenderer struct{}
+
+func (CanonicalRenderer) Render(w io.Writer, recipe *cook.Recipe, opts RenderOptions) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	canonical := ToCanonicalRecipe(recipe)
+	canonical.Nutrition = opts.Nutrition
+	return enc.Encode(canonical)
+}
@@ -0,0 +1,57 @@
+package recipe
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+// Step items are written as in the `result` of the spec's canonical tests, with
+// their defaults ("some" ingredients and 1 cookware), see `CanonicalRecipe`.
+func TestCanonicalRenderer(t *testing.T) {
+	r := cook.ParseRecipeString("", ">> servings: 2\n"+
+		"Add @salt and @flour{1/2%cup} to a #pot{}, bake at 180°C for ~{10%minutes}.")
+	cook.ParseTemperatures(&r)
+
+	var buf bytes.Buffer
+	if err := (CanonicalRenderer{}).Render(&buf, &r, RenderOptions{}); err != nil {
+		t.Fatalf("Failed to render: %v", err)
+	}
+
+	want := `{
+	"ingredients": [
+		{"type": "ingredient", "name": "salt", "quantity": "some", "units": ""},
+		{"type": "ingredient", "name": "flour", "quantity": 0.5, "units": "cup"}
+	],
+	"cookware": [{"type": "cookware", "name": "pot", "quantity": 1, "units": ""}],
+	"steps": [[
+		{"type": "text", "value": "Add "},
+		{"type": "ingredient", "name": "salt", "quantity": "some", "units": ""},
+		{"type": "text", "value": " and "},
+		{"type": "ingredient", "name": "flour", "quantity": 0.5, "units": "cup"},
+		{"type": "text", "value": " to a "},
+		{"type": "cookware", "name": "pot", "quantity": 1, "units": ""},
+		{"type": "text", "value": ", bake at 180°C for "},
+		{"type": "timer", "name": "", "quantity": 10, "units": "minutes"},
+		{"type": "text", "value": "."}
+	]],
+	"metadata": {"servings": "2"}
+}`
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(want)); err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := json.Compact(&got, buf.Bytes()); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if got.String() != compact.String() {
+		t.Fatalf("Wrong canonical JSON\ngot: %v\nwant: %v.", got.String(), compact.String())
+	}
+}
@@ -0,0 +1,194 @@
+package recipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/nutrition"
+)
+
+// A schema.org `Recipe`, see https://schema.org/Recipe
+//
+// Only the properties which can be derived from a cooklang recipe are included.
+type SchemaRecipe struct {
+	Context      string           `json:"@context"`
+	Type         string           `json:"@type"`
+	Name         string           `json:"name"`
+	Description  string           `json:"description,omitempty"`
+	Author       *SchemaThing     `json:"author,omitempty"`
+	Keywords     string           `json:"keywords,omitempty"`
+	Category     string           `json:"recipeCategory,omitempty"`
+	Cuisine      string           `json:"recipeCuisine,omitempty"`
+	Yield        string           `json:"recipeYield,omitempty"`
+	PrepTime     string           `json:"prepTime,omitempty"`
+	CookTime     string           `json:"cookTime,omitempty"`
+	TotalTime    string           `json:"totalTime,omitempty"`
+	Tools        []string         `json:"tool,omitempty"`
+	Ingredients  []string         `json:"recipeIngredient"`
+	Instructions []SchemaStep     `json:"recipeInstructions"`
+	Nutrition    *SchemaNutrition `json:"nutrition,omitempty"`
+}
+
+// A named schema.org entity, e.g. a `Person`
+type SchemaThing struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+// A schema.org `NutritionInformation`, per serving if the servings are known
+type SchemaNutrition struct {
+	Type     string `json:"@type"`
+	Calories string `json:"calories"`
+	Protein  string `json:"proteinContent"`
+	Fat      string `json:"fatContent"`
+	Carbs    string `json:"carbohydrateContent"`
+	Fibre    string `json:"fiberContent"`
+}
+
+// Maps the nutrition of a recipe to a schema.org `NutritionInformation`
+func toSchemaNutrition(facts *nutrition.Facts) *SchemaNutrition {
+	n := facts.Total
+	if facts.PerServing != nil {
+		n = *facts.PerServing
+	}
+	return &SchemaNutrition{
+		Type:     "NutritionInformation",
+		Calories: fmt.Sprintf("%.0f kcal", n.Calories),
+		Protein:  fmt.Sprintf("%.1f g", n.Protein),
+		Fat:      fmt.Sprintf("%.1f g", n.Fat),
+		Carbs:    fmt.Sprintf("%.1f g", n.Carbs),
+		Fibre:    fmt.Sprintf("%.1f g", n.Fibre),
+	}
+}
+
+// A schema.org `HowToStep`
+type SchemaStep struct {
+	Type string `json:"@type"`
+	Text string `json:"text"`
+}
+
+// Returns the first value of any of the metadata `keys`, in order of preference
+func firstMetadata(r *cook.Recipe, keys ...string) (string, bool) {
+	for _, key := range keys {
+		if value, ok := r.Metadata.Get(key); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+// Formats a duration as an ISO 8601 duration, e.g. 90m -> "PT1H30M"
+func ISODuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	hours := int(d / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+
+	out := "PT"
+	if hours > 0 {
+		out += fmt.Sprintf("%dH", hours)
+	}
+	if minutes > 0 {
+		out += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds > 0 || out == "PT" {
+		out += fmt.Sprintf("%dS", seconds)
+	}
+	return out
+}
+
+// Returns the human readable name of an ingredient, recipe references are
+// named after the recipe they reference.
+func ingredientName(ingr cook.Ingredient) string {
+	if ingr.IsRecipeRef() {
+		return FilepathToName(ingr.Name)
+	}
+	return ingr.Name
+}
+
+// Maps a recipe to a schema.org `Recipe`.
+//
+// Times are read from the `prep time`, `cook time` and `time` metadata (in any
+// format accepted by `units.ParseDuration`). Without a total time, the sum of
+// the prep and cook times is used, falling back to the sum of every timer.
+func ToSchemaRecipe(r *cook.Recipe, opts RenderOptions) SchemaRecipe {
+	schema := SchemaRecipe{
+		Context:      "https://schema.org",
+		Type:         "Recipe",
+		Name:         r.Name,
+		Ingredients:  make([]string, 0, len(r.Ingredients)),
+		Instructions: make([]SchemaStep, 0, len(r.Steps)),
+	}
+
+	schema.Description, _ = firstMetadata(r, "description", "introduction")
+	if author, ok := firstMetadata(r, "author", "source.author"); ok {
+		schema.Author = &SchemaThing{Type: "Person", Name: author}
+	}
+	schema.Keywords = strings.Join(Tags(r), ", ")
+	schema.Category, _ = firstMetadata(r, "course", "category")
+	schema.Cuisine, _ = firstMetadata(r, "cuisine")
+
+	if servings, ok := cook.RecipeServings(r); ok {
+		schema.Yield = cook.FormatQty(servings)
+	} else {
+		schema.Yield, _ = firstMetadata(r, "servings", "yield")
+	}
+
+	// Times
+	prep, hasPrep := metadataDuration(r, "prep time", "prep-time", "prep_time")
+	cookTime, hasCook := metadataDuration(r, "cook time", "cook-time", "cook_time")
+	total, hasTotal := TotalTime(r)
+	if hasPrep {
+		schema.PrepTime = ISODuration(prep)
+	}
+	if hasCook {
+		schema.CookTime = ISODuration(cookTime)
+	}
+	if hasTotal {
+		schema.TotalTime = ISODuration(total)
+	}
+
+	for _, cookware := range r.Cookware {
+		schema.Tools = append(schema.Tools, cookware.Name)
+	}
+	for _, ingr := range r.Ingredients {
+		amount := FormatAmount(cook.Component(ingr))
+		schema.Ingredients = append(schema.Ingredients,
+			strings.TrimSpace(amount+" "+ingredientName(ingr)))
+	}
+
+	for _, step := range r.Steps {
+		var text strings.Builder
+		for _, chunk := range step {
+			if ingr, ok := chunk.(cook.Ingredient); ok {
+				text.WriteString(ingredientName(ingr))
+				continue
+			}
+			text.WriteString(ChunkText(chunk, opts))
+		}
+
+		if trimmed := strings.TrimSpace(text.String()); trimmed != "" {
+			schema.Instructions = append(schema.Instructions,
+				SchemaStep{Type: "HowToStep", Text: trimmed})
+		}
+	}
+
+	if opts.Nutrition != nil {
+		schema.Nutrition = toSchemaNutrition(opts.Nutrition)
+	}
+
+	return schema
+}
+
+// Renders a recipe as schema.org `Recipe` JSON-LD, see `ToSchemaRecipe`.
+type JSONLDRenderer struct{}
+
+func (JSONLDRenderer) Render(w io.Writer, recipe *cook.Recipe, opts RenderOptions) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ToSchemaRecipe(recipe, opts))
+}
@@ -0,0 +1,111 @@
+package recipe
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestToSchemaRecipe(t *testing.T) {
+	r := cook.ParseRecipeString("eggs_benedict", ">> description: A brunch classic\n"+
+		">> author: Jane Doe\n>> tags: Brunch, eggs\n>> course: breakfast\n>> cuisine: American\n"+
+		">> servings: 2\n>> prep time: 10 min\n>> cook time: 1h5m\n"+
+		"Poach @eggs{2} in a #pot{} for ~{3%minutes}.\n\n"+
+		"Top with @../sauces/hollandaise{100%g}, @salt{} and bake at 200°C.\n\n"+
+		"-- Just a comment\n")
+	cook.ParseTemperatures(&r)
+
+	got := ToSchemaRecipe(&r, RenderOptions{Units: units.Metric})
+	want := SchemaRecipe{
+		Context:     "https://schema.org",
+		Type:        "Recipe",
+		Name:        "eggs_benedict",
+		Description: "A brunch classic",
+		Author:      &SchemaThing{Type: "Person", Name: "Jane Doe"},
+		Keywords:    "brunch, eggs",
+		Category:    "breakfast",
+		Cuisine:     "American",
+		Yield:       "2",
+		PrepTime:    "PT10M",
+		CookTime:    "PT1H5M",
+		TotalTime:   "PT1H15M",
+		Tools:       []string{"pot"},
+		Ingredients: []string{"2 eggs", "100 g hollandaise", "salt"},
+		Instructions: []SchemaStep{
+			{Type: "HowToStep", Text: "Poach eggs in a pot for 3 minutes."},
+			{Type: "HowToStep", Text: "Top with hollandaise, salt and bake at 200°C."},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Wrong schema recipe\ngot: %+v\nwant: %+v.", got, want)
+	}
+}
+
+func TestSchemaRecipeTimes(t *testing.T) {
+	testTimes := func(source string, prep string, cookTime string, total string) {
+		r := cook.ParseRecipeString("", source)
+		got := ToSchemaRecipe(&r, RenderOptions{})
+		if got.PrepTime != prep || got.CookTime != cookTime || got.TotalTime != total {
+			t.Fatalf("Wrong times of %q\ngot: %q, %q, %q\nwant: %q, %q, %q.", source,
+				got.PrepTime, got.CookTime, got.TotalTime, prep, cookTime, total)
+		}
+	}
+
+	testTimes(">> time: 2 hours\n>> prep time: 20 min\nServe.", "PT20M", "", "PT2H")
+	testTimes(">> cook time: 45 min\nServe.", "", "PT45M", "PT45M")
+	testTimes("Boil ~{10%minutes}, then rest ~{90%seconds}.", "", "", "PT11M30S")
+	testTimes(">> prep time: soon\nBoil ~{1%hour}.", "", "", "PT1H")
+	testTimes("Serve ~{a while}.", "", "", "")
+}
+
+func TestISODuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                                  "PT0S",
+		45 * time.Second:                   "PT45S",
+		90 * time.Minute:                   "PT1H30M",
+		2*time.Hour + 5*time.Second:        "PT2H5S",
+		26 * time.Hour:                     "PT26H",
+		time.Minute + 400*time.Millisecond: "PT1M",
+	} {
+		if got := ISODuration(d); got != want {
+			t.Fatalf("Wrong ISO duration of %v\ngot: %v\nwant: %v.", d, got, want)
+		}
+	}
+}
+
+func TestJSONLDRenderer(t *testing.T) {
+	r := cook.ParseRecipeString("toast", ">> servings: 1\nToast @bread{2%slices} for ~{3%min}.")
+
+	var sb strings.Builder
+	if err := (JSONLDRenderer{}).Render(&sb, &r, RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(sb.String()), &got); err != nil {
+		t.Fatalf("Invalid JSON-LD: %v\n%v", err, sb.String())
+	}
+	want := map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            "Recipe",
+		"name":             "toast",
+		"recipeYield":      "1",
+		"totalTime":        "PT3M",
+		"recipeIngredient": []any{"2 slices bread"},
+		"recipeInstructions": []any{
+			map[string]any{"@type": "HowToStep", "text": "Toast bread for 3 min."},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Wrong JSON-LD\ngot: %v\nwant: %v.", got, want)
+	}
+}
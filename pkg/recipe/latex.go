@@ -0,0 +1,120 @@
+package recipe
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// Escapes characters with special meaning in LaTeX
+var latexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, `{`, `\{`, `}`, `\}`, `$`, `\$`, `&`, `\&`, `#`, `\#`,
+	`%`, `\%`, `_`, `\_`, `~`, `\textasciitilde{}`, `^`, `\textasciicircum{}`,
+	`°`, `\textdegree{}`)
+
+// Escapes text for use within a LaTeX document
+func LaTeXEscape(text string) string {
+	return latexEscaper.Replace(text)
+}
+
+// Returns the preamble of a LaTeX document of `class` (e.g. "article" or
+// "book"), up to and including `\begin{document}`.
+//
+// Sections aren't numbered, recipes are found by name rather than number.
+func LaTeXPreamble(class string, title string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\\documentclass[11pt]{%v}\n", class))
+	sb.WriteString("\\usepackage[utf8]{inputenc}\n")
+	sb.WriteString("\\usepackage[T1]{fontenc}\n")
+	sb.WriteString("\\usepackage{textcomp}\n")
+	sb.WriteString("\\usepackage[margin=2cm]{geometry}\n")
+	sb.WriteString("\\usepackage{paracol}\n")
+	sb.WriteString("\\usepackage{enumitem}\n")
+	sb.WriteString("\\setcounter{secnumdepth}{0}\n")
+	sb.WriteString("\\setlist{nosep}\n")
+	sb.WriteString("\\setcolumnwidth{0.3\\textwidth,0.65\\textwidth}\n")
+	sb.WriteString(fmt.Sprintf("\\title{%v}\n", LaTeXEscape(title)))
+	sb.WriteString("\\date{}\n\n")
+	sb.WriteString("\\begin{document}\n\n")
+	return sb.String()
+}
+
+// Returns a recipe as a LaTeX section: its metadata as a header, followed by
+// its ingredients and cookware beside its steps (in two columns).
+func LaTeXRecipe(recipe *cook.Recipe, opts RenderOptions) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("\\section{%v}\n", LaTeXEscape(recipe.Name)))
+
+	if len(recipe.Metadata) > 0 {
+		entries := make([]string, 0, len(recipe.Metadata))
+		for _, entry := range recipe.Metadata {
+			entries = append(entries, fmt.Sprintf("\\textbf{%v:} %v",
+				LaTeXEscape(entry.Key), LaTeXEscape(strings.Join(entry.Values, ", "))))
+		}
+		sb.WriteString("{\\small\\noindent ")
+		sb.WriteString(strings.Join(entries, " \\quad\n"))
+		sb.WriteString("\\par}\n\\medskip\n")
+	}
+
+	sb.WriteString("\\begin{paracol}{2}\n")
+	if len(recipe.Ingredients) > 0 {
+		sb.WriteString("\\subsection*{Ingredients}\n\\begin{itemize}\n")
+		for _, ingr := range recipe.Ingredients {
+			sb.WriteString(latexListItem(cook.Component(ingr)))
+		}
+		sb.WriteString("\\end{itemize}\n")
+	}
+	if len(recipe.Cookware) > 0 {
+		sb.WriteString("\\subsection*{Cookware}\n\\begin{itemize}\n")
+		for _, cookware := range recipe.Cookware {
+			sb.WriteString(latexListItem(cook.Component(cookware)))
+		}
+		sb.WriteString("\\end{itemize}\n")
+	}
+
+	sb.WriteString("\\switchcolumn\n")
+	if len(recipe.Steps) > 0 {
+		sb.WriteString("\\subsection*{Steps}\n\\begin{enumerate}\n")
+		for _, step := range recipe.Steps {
+			sb.WriteString("  \\item ")
+			for _, chunk := range step {
+				text := LaTeXEscape(ChunkText(chunk, opts))
+				switch chunk.(type) {
+				case cook.Ingredient:
+					text = "\\textbf{" + text + "}"
+				case cook.Cookware:
+					text = "\\emph{" + text + "}"
+				}
+				sb.WriteString(text)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\\end{enumerate}\n")
+	}
+	sb.WriteString("\\end{paracol}\n")
+
+	return sb.String()
+}
+
+// Formats a component as a LaTeX list item, e.g. "\item 125 g flour"
+func latexListItem(c cook.Component) string {
+	item := "  \\item "
+	if qty := FormatAmount(c); qty != "" {
+		item += LaTeXEscape(qty) + " "
+	}
+	return item + LaTeXEscape(c.Name) + "\n"
+}
+
+// Renders a recipe as a standalone LaTeX document, ready for `pdflatex`.
+type LaTeXRenderer struct{}
+
+func (LaTeXRenderer) Render(w io.Writer, recipe *cook.Recipe, opts RenderOptions) error {
+	doc := LaTeXPreamble("article", recipe.Name) +
+		LaTeXRecipe(recipe, opts) +
+		"\n\\end{document}\n"
+	_, err := io.WriteString(w, doc)
+	return err
+}
@@ -0,0 +1,75 @@
+package recipe
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// Escapes characters which would otherwise be read as markdown formatting
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`, `#`, `\#`)
+
+// Renders a recipe as Markdown, with headed ingredient and cookware lists
+// followed by numbered steps (with ingredients in bold).
+type MarkdownRenderer struct{}
+
+func (MarkdownRenderer) Render(w io.Writer, recipe *cook.Recipe, opts RenderOptions) error {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %v\n\n", mdEscaper.Replace(recipe.Name)))
+
+	if len(recipe.Metadata) > 0 {
+		for _, entry := range recipe.Metadata {
+			sb.WriteString(fmt.Sprintf("- **%v:** %v\n",
+				mdEscaper.Replace(entry.Key),
+				mdEscaper.Replace(strings.Join(entry.Values, ", "))))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(recipe.Ingredients) > 0 {
+		sb.WriteString("## Ingredients\n\n")
+		for _, ingr := range recipe.Ingredients {
+			sb.WriteString(mdListItem(cook.Component(ingr)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(recipe.Cookware) > 0 {
+		sb.WriteString("## Cookware\n\n")
+		for _, cookware := range recipe.Cookware {
+			sb.WriteString(mdListItem(cook.Component(cookware)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(recipe.Steps) > 0 {
+		sb.WriteString("## Steps\n\n")
+		for n, step := range recipe.Steps {
+			sb.WriteString(fmt.Sprintf("%v. ", n+1))
+			for _, chunk := range step {
+				text := mdEscaper.Replace(ChunkText(chunk, opts))
+				if _, ok := chunk.(cook.Ingredient); ok {
+					text = "**" + text + "**"
+				}
+				sb.WriteString(text)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Formats a component as a markdown list item, e.g. "- flour (125 g)"
+func mdListItem(c cook.Component) string {
+	item := "- " + mdEscaper.Replace(c.Name)
+	if qty := FormatAmount(c); qty != "" {
+		item += fmt.Sprintf(" (%v)", mdEscaper.Replace(qty))
+	}
+	return item + "\n"
+}
@@ -5,10 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"text/tabwriter"
+	"time"
 
 	"git.sr.ht/~rottenfishbone/go-cook"
 	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
 )
 
 // Reads a recipe file and parses it into a Recipe struct.
@@ -34,7 +35,7 @@ func FilepathToName(path string) string {
 	path = filepath.Base(path)
 	ext := filepath.Ext(path)
 
-	path = strings.TrimRight(path, ext)
+	path = strings.TrimSuffix(path, ext)
 	path = strings.Map(func(r rune) rune {
 		if r == '_' || r == '-' {
 			return ' '
@@ -46,62 +47,111 @@ func FilepathToName(path string) string {
 }
 
 // Prints a recipe to stdout using nice formatting.
+//
+// See `TextRenderer` to render elsewhere.
 func PrettyPrint(recipe *cook.Recipe) {
-	fmt.Printf("========= %v ========\n", recipe.Name)
-	wr := new(tabwriter.Writer)
-	if len(recipe.Metadata) > 0 {
-		fmt.Println("Metadata:")
-		for k, v := range recipe.Metadata {
-			fmt.Printf("\t%v: %v\n", k, v)
-		}
-		fmt.Println("")
+	if err := (TextRenderer{}).Render(os.Stdout, recipe, RenderOptions{}); err != nil {
+		common.ShowError(err)
 	}
+}
 
-	if len(recipe.Ingredients) > 0 {
-		fmt.Println("Ingredients:")
-		wr.Init(os.Stdout, 0, 4, 4, ' ', tabwriter.TabIndent)
-		for _, ingr := range recipe.Ingredients {
-			var qtyStr string
-			if ingr.QtyVal != cook.NoQty {
-				qtyStr = fmt.Sprintf("%v %v", ingr.QtyVal, ingr.Unit)
-			} else {
-				qtyStr = ingr.Qty + " " + ingr.Unit
+// Returns the tags of a recipe, as defined by its `tag` and `tags` metadata.
+//
+// Each value may be a comma delimited list. Tags are lowercased, trimmed and
+// de-duplicated, in the order they first appear.
+func Tags(r *cook.Recipe) []string {
+	tags := make([]string, 0)
+	seen := map[string]bool{}
+	for _, key := range []string{"tag", "tags"} {
+		for _, value := range r.Metadata.GetAll(key) {
+			for _, tag := range strings.Split(value, ",") {
+				tag = strings.ToLower(strings.TrimSpace(tag))
+				if tag != "" && !seen[tag] {
+					seen[tag] = true
+					tags = append(tags, tag)
+				}
 			}
-
-			fmt.Fprintf(wr, "\t%v\t%v\n", ingr.Name, qtyStr)
 		}
-		wr.Flush()
-		fmt.Println("")
 	}
+	return tags
+}
 
-	if len(recipe.Cookware) > 0 {
-		fmt.Println("Cookware:")
-		wr.Init(os.Stdout, 0, 4, 4, ' ', tabwriter.TabIndent)
-		for _, cookware := range recipe.Cookware {
-			var qtyStr string
-			if cookware.QtyVal != cook.NoQty {
-				qtyStr = fmt.Sprintf("%v %v", cookware.QtyVal, cookware.Unit)
-			} else {
-				qtyStr = cookware.Qty + " " + cookware.Unit
+// Returns the names (lowercased) of the optional ingredients of a recipe, those
+// which are followed by "(optional)" wherever they are used, e.g.
+//
+//	Top with @chives{}(optional).
+func OptionalIngredients(r *cook.Recipe) map[string]bool {
+	optional := map[string]bool{}
+	required := map[string]bool{}
+	for _, step := range r.Steps {
+		for i, chunk := range step {
+			ingr, ok := chunk.(cook.Ingredient)
+			if !ok {
+				continue
 			}
 
-			fmt.Fprintf(wr, "\t%v\t%v\n", cookware.Name, qtyStr)
+			name := strings.ToLower(strings.TrimSpace(ingr.Name))
+			next := cook.Text("")
+			if i+1 < len(step) {
+				next, _ = step[i+1].(cook.Text)
+			}
+			if strings.HasPrefix(strings.ToLower(strings.TrimSpace(string(next))), "(optional") {
+				optional[name] = true
+			} else {
+				required[name] = true
+			}
 		}
-		wr.Flush()
-		fmt.Println("")
 	}
 
-	if len(recipe.Steps) > 0 {
-		fmt.Println("Steps:")
-		var builder strings.Builder
-		for n, step := range recipe.Steps {
-			fmt.Printf("\t%v. ", n+1)
-			for _, chunk := range step {
-				builder.WriteString(chunk.ToString())
+	for name := range required {
+		delete(optional, name)
+	}
+	return optional
+}
+
+// Reads a duration from the first of the metadata `keys` which parses as one
+func metadataDuration(r *cook.Recipe, keys ...string) (time.Duration, bool) {
+	for _, key := range keys {
+		if value, ok := r.Metadata.Get(key); ok {
+			if d, err := units.ParseDuration(value); err == nil {
+				return d, true
 			}
-			fmt.Println(builder.String())
-			builder.Reset()
 		}
-		fmt.Println("")
 	}
+	return 0, false
+}
+
+// Sums the duration of every timer in a recipe. Timers without a time unit
+// are ignored.
+func timersDuration(r *cook.Recipe) time.Duration {
+	var total time.Duration
+	for _, timer := range r.Timers {
+		if timer.QtyVal == cook.NoQty {
+			continue
+		}
+		if d, err := units.ToDuration(timer.QtyVal, timer.Unit); err == nil {
+			total += d
+		}
+	}
+	return total
+}
+
+// Returns the total time a recipe takes, read from its `time` metadata (in any
+// format accepted by `units.ParseDuration`). Without one, the sum of its
+// `prep time` and `cook time` is used, falling back to the sum of every timer.
+//
+// Returns false if none are known.
+func TotalTime(r *cook.Recipe) (time.Duration, bool) {
+	if total, ok := metadataDuration(r, "time", "total time", "total-time", "total_time", "duration"); ok {
+		return total, true
+	}
+
+	prep, hasPrep := metadataDuration(r, "prep time", "prep-time", "prep_time")
+	cookTime, hasCook := metadataDuration(r, "cook time", "cook-time", "cook_time")
+	if hasPrep || hasCook {
+		return prep + cookTime, true
+	}
+
+	total := timersDuration(r)
+	return total, total > 0
 }
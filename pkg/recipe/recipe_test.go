@@ -0,0 +1,38 @@
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestOptionalIngredients(t *testing.T) {
+	r := cook.ParseRecipeString("", "Cook the @pasta{500%g} with @Chives{} (optional).\n\n"+
+		"Top with @cheese{}(Optional, to taste) and @salt{}.\n\n"+
+		"Season with @salt{} (optional).")
+
+	got := OptionalIngredients(&r)
+	if len(got) != 2 || !got["chives"] || !got["cheese"] {
+		t.Fatalf("Wrong optional ingredients\ngot: %v\nwant: %v.", got, "chives, cheese")
+	}
+}
+
+func TestTotalTime(t *testing.T) {
+	testTime := func(source string, want time.Duration, wantOk bool) {
+		r := cook.ParseRecipeString("", source)
+		if got, ok := TotalTime(&r); got != want || ok != wantOk {
+			t.Fatalf("Wrong total time of %q\ngot: %v (%v)\nwant: %v (%v).",
+				source, got, ok, want, wantOk)
+		}
+	}
+
+	testTime(">> time: 1h30m\n>> prep time: 10 min\nBake ~{20%minutes}.", 90*time.Minute, true)
+	testTime(">> prep time: 10 min\n>> cook time: 20 min\n", 30*time.Minute, true)
+	testTime("Bake ~{20%minutes}, then rest ~{1%hour}.", 80*time.Minute, true)
+	testTime("Serve.", 0, false)
+}
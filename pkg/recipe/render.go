@@ -0,0 +1,129 @@
+package recipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/nutrition"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+// Options shared by each `Renderer`
+type RenderOptions struct {
+	// The unit system temperatures are shown in (`units.Metric` or
+	// `units.Imperial`). Both scales are shown if left empty.
+	Units string
+	// Whether temperatures in step text should be parsed into
+	// `cook.Temperature` chunks (see `cook.ParseTemperatures`) before rendering.
+	// Renderers only show temperatures which were parsed.
+	Temperatures bool
+	// The recipe's nutrition, included in the output of the JSON renderers
+	// (`json`, `jsonld` and `canonical-json`) when set.
+	Nutrition *nutrition.Facts
+}
+
+// A Renderer writes a recipe to `w` in a specific format.
+type Renderer interface {
+	Render(w io.Writer, recipe *cook.Recipe, opts RenderOptions) error
+}
+
+// The manifest of each renderer, keyed by the name used to select it.
+// e.g. `cook read --format md`
+var renderers = map[string]Renderer{
+	"text":           TextRenderer{},
+	"md":             MarkdownRenderer{},
+	"json":           JSONRenderer{},
+	"jsonld":         JSONLDRenderer{},
+	"latex":          LaTeXRenderer{},
+	"typst":          TypstRenderer{},
+	"canonical-json": CanonicalRenderer{},
+}
+
+// Returns the renderer registered as `format`.
+//
+// Returns an error listing the available formats if none match.
+func GetRenderer(format string) (Renderer, error) {
+	renderer, ok := renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("Unknown format %q, expected one of: %v",
+			format, strings.Join(Formats(), ", "))
+	}
+	return renderer, nil
+}
+
+// Returns the name of every registered renderer, sorted.
+func Formats() []string {
+	formats := make([]string, 0, len(renderers))
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Formats the quantity and unit of a component, e.g. "125 g" or "a pinch".
+//
+// Parsed quantities are shown as numbers (e.g. "1/2" -> "0.5").
+func FormatAmount(c cook.Component) string {
+	var qty string
+	if c.QtyVal != cook.NoQty {
+		qty = cook.FormatQty(c.QtyVal)
+	} else {
+		qty = c.Qty
+	}
+	return strings.TrimSpace(qty + " " + c.Unit)
+}
+
+// Formats a temperature for the unit system in `opts`. If no system is set,
+// both celsius and fahrenheit are shown with the written scale first.
+// e.g. "200°C (392°F)"
+func formatTemperature(temp cook.Temperature, opts RenderOptions) string {
+	switch opts.Units {
+	case units.Metric:
+		return temp.Convert(cook.Celsius).Raw
+	case units.Imperial:
+		return temp.Convert(cook.Fahrenheit).Raw
+	}
+
+	other := cook.Fahrenheit
+	if temp.Scale == cook.Fahrenheit {
+		other = cook.Celsius
+	}
+	return fmt.Sprintf("%v (%v)", temp.Raw, temp.Convert(other).Raw)
+}
+
+// Returns the duration of a timer, e.g. "10 minutes", or its name if it has none.
+func TimerText(timer cook.Timer) string {
+	if qty := FormatAmount(cook.Component(timer)); qty != "" {
+		return qty
+	}
+	return timer.Name
+}
+
+// Returns the human readable text of a step chunk.
+func ChunkText(chunk cook.Chunk, opts RenderOptions) string {
+	switch chunk := chunk.(type) {
+	case cook.Temperature:
+		return formatTemperature(chunk, opts)
+	case cook.Timer:
+		return TimerText(chunk)
+	default:
+		return chunk.ToString()
+	}
+}
+
+// Renders a recipe as JSON, using the same encoding as the API.
+type JSONRenderer struct{}
+
+func (JSONRenderer) Render(w io.Writer, recipe *cook.Recipe, opts RenderOptions) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		*cook.Recipe
+		Nutrition *nutrition.Facts `json:"nutrition,omitempty"`
+	}{recipe, opts.Nutrition})
+}
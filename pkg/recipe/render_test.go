@@ -0,0 +1,84 @@
+package recipe
+
+import (
+	"strings"
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+const renderTestSource = ">> servings: 2\n>> tags: quick, easy\n" +
+	"Mix @flour{1/2%cup} and @salt{a pinch} in a #bowl{}.\n\n" +
+	"Bake at 180°C for ~bake{20%minutes}, then rest ~{5%min}.\n"
+
+// Renders `renderTestSource` (as "pan_cakes") with `renderer`, failing if the
+// output differs from `want`.
+func testRender(t *testing.T, renderer Renderer, opts RenderOptions, want string) {
+	r := cook.ParseRecipeString("pan_cakes", renderTestSource)
+	cook.ParseTemperatures(&r)
+
+	var sb strings.Builder
+	if err := renderer.Render(&sb, &r, opts); err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != want {
+		t.Fatalf("Wrong render\ngot:\n%v\nwant:\n%v.", sb.String(), want)
+	}
+}
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestTextRenderer(t *testing.T) {
+	testRender(t, TextRenderer{}, RenderOptions{}, `========= pan_cakes ========
+Metadata:
+	servings: 2
+	tags: quick, easy
+
+Ingredients:
+	flour    0.5 cup
+	salt     a pinch
+
+Cookware:
+`+"\tbowl    \n"+`
+Steps:
+	1. Mix flour and salt in a bowl.
+	2. Bake at 180°C (356°F) for 20 minutes, then rest 5 min.
+
+`)
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	testRender(t, MarkdownRenderer{}, RenderOptions{Units: units.Imperial}, `# pan\_cakes
+
+- **servings:** 2
+- **tags:** quick, easy
+
+## Ingredients
+
+- flour (0.5 cup)
+- salt (a pinch)
+
+## Cookware
+
+- bowl
+
+## Steps
+
+1. Mix **flour** and **salt** in a bowl.
+2. Bake at 356°F for 20 minutes, then rest 5 min.
+`)
+}
+
+func TestTimerText(t *testing.T) {
+	r := cook.ParseRecipeString("", "Bake for ~bake{20%minutes}, rest ~{5%min} and ~cool{}.")
+	got := make([]string, 0, len(r.Timers))
+	for _, timer := range r.Timers {
+		got = append(got, TimerText(timer))
+	}
+	if want := "20 minutes, 5 min, cool"; strings.Join(got, ", ") != want {
+		t.Fatalf("Wrong timer text\ngot: %v\nwant: %v.", strings.Join(got, ", "), want)
+	}
+}
@@ -0,0 +1,64 @@
+package recipe
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// Renders a recipe as plain text, laid out for a terminal.
+type TextRenderer struct{}
+
+func (TextRenderer) Render(w io.Writer, recipe *cook.Recipe, opts RenderOptions) error {
+	fmt.Fprintf(w, "========= %v ========\n", recipe.Name)
+	wr := new(tabwriter.Writer)
+	if len(recipe.Metadata) > 0 {
+		fmt.Fprintln(w, "Metadata:")
+		for _, entry := range recipe.Metadata {
+			fmt.Fprintf(w, "\t%v: %v\n", entry.Key, strings.Join(entry.Values, ", "))
+		}
+		fmt.Fprintln(w, "")
+	}
+
+	if len(recipe.Ingredients) > 0 {
+		fmt.Fprintln(w, "Ingredients:")
+		wr.Init(w, 0, 4, 4, ' ', tabwriter.TabIndent)
+		for _, ingr := range recipe.Ingredients {
+			fmt.Fprintf(wr, "\t%v\t%v\n", ingr.Name, FormatAmount(cook.Component(ingr)))
+		}
+		if err := wr.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "")
+	}
+
+	if len(recipe.Cookware) > 0 {
+		fmt.Fprintln(w, "Cookware:")
+		wr.Init(w, 0, 4, 4, ' ', tabwriter.TabIndent)
+		for _, cookware := range recipe.Cookware {
+			fmt.Fprintf(wr, "\t%v\t%v\n", cookware.Name, FormatAmount(cook.Component(cookware)))
+		}
+		if err := wr.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "")
+	}
+
+	if len(recipe.Steps) > 0 {
+		fmt.Fprintln(w, "Steps:")
+		var builder strings.Builder
+		for n, step := range recipe.Steps {
+			for _, chunk := range step {
+				builder.WriteString(ChunkText(chunk, opts))
+			}
+			fmt.Fprintf(w, "\t%v. %v\n", n+1, builder.String())
+			builder.Reset()
+		}
+		fmt.Fprintln(w, "")
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package recipe
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+)
+
+// Escapes characters with special meaning in Typst markup
+var typstEscaper = strings.NewReplacer(
+	`\`, `\\`, `*`, `\*`, `_`, `\_`, "`", "\\`", `$`, `\$`, `#`, `\#`, `@`, `\@`,
+	`<`, `\<`, `>`, `\>`, `[`, `\[`, `]`, `\]`, `~`, `\~`, `/`, `\/`, `=`, `\=`,
+	`-`, `\-`, `+`, `\+`)
+
+// Escapes text for use within Typst markup
+func TypstEscape(text string) string {
+	return typstEscaper.Replace(text)
+}
+
+// Quotes text as a Typst string literal, e.g. `"Mom's \"best\" pie"`
+func TypstString(text string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(text) + `"`
+}
+
+// Returns the preamble of a Typst document, setting its title and page style.
+func TypstPreamble(title string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("#set document(title: %v)\n", TypstString(title)))
+	sb.WriteString("#set page(paper: \"a4\", margin: 2cm)\n")
+	sb.WriteString("#set text(size: 11pt)\n\n")
+	return sb.String()
+}
+
+// Returns a recipe as Typst markup, headed at `level` (e.g. 1 for `= Name`):
+// its metadata as a header, followed by its ingredients and cookware beside
+// its steps (in two columns).
+func TypstRecipe(recipe *cook.Recipe, opts RenderOptions, level int) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("%v %v\n\n", strings.Repeat("=", level), TypstEscape(recipe.Name)))
+
+	if len(recipe.Metadata) > 0 {
+		entries := make([]string, 0, len(recipe.Metadata))
+		for _, entry := range recipe.Metadata {
+			entries = append(entries, fmt.Sprintf("*%v:* %v",
+				TypstEscape(entry.Key), TypstEscape(strings.Join(entry.Values, ", "))))
+		}
+		sb.WriteString("#text(size: 0.9em)[")
+		sb.WriteString(strings.Join(entries, " #h(1em) "))
+		sb.WriteString("]\n\n")
+	}
+
+	subheading := strings.Repeat("=", level+1)
+	sb.WriteString("#grid(columns: (1fr, 2fr), column-gutter: 1.5em,\n[\n")
+	if len(recipe.Ingredients) > 0 {
+		sb.WriteString(subheading + " Ingredients\n")
+		for _, ingr := range recipe.Ingredients {
+			sb.WriteString(typstListItem(cook.Component(ingr)))
+		}
+		sb.WriteString("\n")
+	}
+	if len(recipe.Cookware) > 0 {
+		sb.WriteString(subheading + " Cookware\n")
+		for _, cookware := range recipe.Cookware {
+			sb.WriteString(typstListItem(cook.Component(cookware)))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("],\n[\n")
+	if len(recipe.Steps) > 0 {
+		sb.WriteString(subheading + " Steps\n")
+		for _, step := range recipe.Steps {
+			sb.WriteString("+ ")
+			for _, chunk := range step {
+				text := TypstEscape(ChunkText(chunk, opts))
+				// The `;` ends the call, so following text isn't read as code
+				switch chunk.(type) {
+				case cook.Ingredient:
+					text = "#strong[" + text + "];"
+				case cook.Cookware:
+					text = "#emph[" + text + "];"
+				}
+				sb.WriteString(text)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("])\n")
+
+	return sb.String()
+}
+
+// Formats a component as a Typst list item, e.g. "- 125 g flour"
+func typstListItem(c cook.Component) string {
+	item := "- "
+	if qty := FormatAmount(c); qty != "" {
+		item += TypstEscape(qty) + " "
+	}
+	return item + TypstEscape(c.Name) + "\n"
+}
+
+// Renders a recipe as a standalone Typst document, ready for `typst compile`.
+type TypstRenderer struct{}
+
+func (TypstRenderer) Render(w io.Writer, recipe *cook.Recipe, opts RenderOptions) error {
+	doc := TypstPreamble(recipe.Name) + TypstRecipe(recipe, opts, 1)
+	_, err := io.WriteString(w, doc)
+	return err
+}
@@ -0,0 +1,261 @@
+// Package schedule plans when to start each step of one or more recipes, so
+// that everything is ready at the same time.
+//
+// Steps are scheduled backwards from the ready time, each as late as it can
+// be. A step starts with hands-on (active) work, followed by its timers
+// (passive), during which the cook is free to work on other steps. Only one
+// step is worked on at a time, and exclusive cookware (e.g. the oven) is held
+// by a single step at a time.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The hands-on time of a step, unless given
+const DefaultActive = 5 * time.Minute
+
+var ErrCycle = errors.New("Steps depend on each other in a cycle.")
+
+// A recipe step to be scheduled
+type Task struct {
+	Recipe   string
+	Step     int // 1-based
+	Text     string
+	Active   time.Duration // Hands-on time, at the start of the step
+	Passive  time.Duration // Timers, during which the cook is free
+	Cookware []string      // Exclusive cookware, held for the whole step
+	After    []int         // Indices of the tasks which must finish first
+}
+
+// Returns how long a task takes from start to finish
+func (t Task) duration() time.Duration {
+	return t.Active + t.Passive
+}
+
+// A scheduled step
+type Entry struct {
+	Recipe    string    `json:"recipe"`
+	Step      int       `json:"step"`
+	Text      string    `json:"text"`
+	Start     time.Time `json:"start"`
+	HandsFree time.Time `json:"handsFree"` // When the hands-on work is done
+	End       time.Time `json:"end"`
+	Cookware  []string  `json:"cookware,omitempty"`
+}
+
+// The steps of every recipe, by start time
+type Schedule struct {
+	Ready   time.Time `json:"ready"`
+	Start   time.Time `json:"start"` // When the first step starts
+	Entries []Entry   `json:"entries"`
+}
+
+// A span of time something is busy for
+type interval struct {
+	start, end time.Time
+}
+
+func (i interval) overlaps(start time.Time, end time.Time) bool {
+	return start.Before(i.end) && i.start.Before(end)
+}
+
+// Schedules every task to finish by `ready`, each as late as possible.
+//
+// Tasks are placed in reverse, always picking the one which can finish the
+// latest (breaking ties by the longest chain of work before it). Each is moved
+// earlier until its hands-on time doesn't overlap another's and its cookware
+// is free.
+//
+// Returns `ErrCycle` if the tasks' dependencies form a cycle.
+func Backward(tasks []Task, ready time.Time) (Schedule, error) {
+	var err error
+	var chains []time.Duration
+	if chains, err = chainLengths(tasks); err != nil {
+		return Schedule{}, err
+	}
+
+	// The number of unscheduled tasks waiting on each task
+	waiting := make([]int, len(tasks))
+	for _, task := range tasks {
+		for _, before := range task.After {
+			waiting[before]++
+		}
+	}
+
+	bounds := make([]time.Time, len(tasks))
+	for i := range bounds {
+		bounds[i] = ready
+	}
+	scheduled := make([]bool, len(tasks))
+	entries := make([]Entry, 0, len(tasks))
+	hands := []interval{}
+	cookware := map[string][]interval{}
+
+	for len(entries) < len(tasks) {
+		next := -1
+		for i := range tasks {
+			if scheduled[i] || waiting[i] > 0 {
+				continue
+			}
+			if next < 0 || bounds[i].After(bounds[next]) ||
+				(bounds[i].Equal(bounds[next]) && chains[i] > chains[next]) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return Schedule{}, ErrCycle
+		}
+
+		task := tasks[next]
+		end := latestEnd(task, bounds[next], hands, cookware)
+		start := end.Add(-task.duration())
+		handsFree := start.Add(task.Active)
+
+		if task.Active > 0 {
+			hands = append(hands, interval{start, handsFree})
+		}
+		for _, name := range task.Cookware {
+			cookware[name] = append(cookware[name], interval{start, end})
+		}
+		for _, before := range task.After {
+			waiting[before]--
+			if start.Before(bounds[before]) {
+				bounds[before] = start
+			}
+		}
+
+		scheduled[next] = true
+		entries = append(entries, Entry{
+			Recipe:    task.Recipe,
+			Step:      task.Step,
+			Text:      task.Text,
+			Start:     start,
+			HandsFree: handsFree,
+			End:       end,
+			Cookware:  task.Cookware,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Start.Equal(entries[j].Start) {
+			return entries[i].Start.Before(entries[j].Start)
+		}
+		if entries[i].Recipe != entries[j].Recipe {
+			return entries[i].Recipe < entries[j].Recipe
+		}
+		return entries[i].Step < entries[j].Step
+	})
+
+	s := Schedule{Ready: ready, Start: ready, Entries: entries}
+	if len(entries) > 0 {
+		s.Start = entries[0].Start
+	}
+	return s, nil
+}
+
+// Returns the latest a task can finish by `bound`, without its hands-on time
+// overlapping `hands` or its cookware being in use.
+func latestEnd(task Task, bound time.Time, hands []interval, cookware map[string][]interval) time.Time {
+	end := bound
+	for moved := true; moved; {
+		moved = false
+		start := end.Add(-task.duration())
+
+		if task.Active > 0 {
+			for _, busy := range hands {
+				if busy.overlaps(start, start.Add(task.Active)) {
+					// Finish the hands-on work as the other starts
+					end = busy.start.Add(-task.Active).Add(task.duration())
+					start = end.Add(-task.duration())
+					moved = true
+				}
+			}
+		}
+		for _, name := range task.Cookware {
+			for _, busy := range cookware[name] {
+				if busy.overlaps(start, end) {
+					end = busy.start
+					start = end.Add(-task.duration())
+					moved = true
+				}
+			}
+		}
+	}
+	return end
+}
+
+// Returns the length of the longest chain of tasks leading up to (and
+// including) each task.
+func chainLengths(tasks []Task) ([]time.Duration, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(tasks))
+	lengths := make([]time.Duration, len(tasks))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return ErrCycle
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+
+		var longest time.Duration
+		for _, before := range tasks[i].After {
+			if before < 0 || before >= len(tasks) {
+				return fmt.Errorf("Step %v of %v depends on an unknown task %v",
+					tasks[i].Step, tasks[i].Recipe, before)
+			}
+			if err := visit(before); err != nil {
+				return err
+			}
+			if lengths[before] > longest {
+				longest = lengths[before]
+			}
+		}
+
+		lengths[i] = longest + tasks[i].duration()
+		state[i] = visited
+		return nil
+	}
+
+	for i := range tasks {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return lengths, nil
+}
+
+// Matches a ready time with an optional date, e.g. "2026-10-20 19:00"
+var readyRegex = regexp.MustCompile(`^(?:(\d{4}-\d{2}-\d{2})[ T])?(\d{1,2}:\d{2})$`)
+
+// Parses the time everything should be ready at, e.g. "19:00" (today) or
+// "2026-10-20 19:00". Times are local to `now`.
+func ParseReady(text string, now time.Time) (time.Time, error) {
+	match := readyRegex.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return time.Time{}, fmt.Errorf("Invalid ready time %q, expected e.g. 19:00", text)
+	}
+
+	date := now.Format("2006-01-02")
+	if match[1] != "" {
+		date = match[1]
+	}
+	ready, err := time.ParseInLocation("2006-01-02 15:04", date+" "+match[2], now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid ready time %q, expected e.g. 19:00", text)
+	}
+	return ready, nil
+}
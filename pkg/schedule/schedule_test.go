@@ -0,0 +1,89 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestBackward(t *testing.T) {
+	ready := time.Date(2026, 10, 20, 19, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		// A sauce which simmers while the cook is busy elsewhere
+		{Recipe: "sauce", Step: 1, Active: 10 * time.Minute},
+		{Recipe: "sauce", Step: 2, Active: 5 * time.Minute, Passive: 60 * time.Minute, After: []int{0}},
+		// A main using the sauce, baked in the oven
+		{Recipe: "main", Step: 1, Active: 10 * time.Minute, After: []int{1}},
+		{Recipe: "main", Step: 2, Active: 5 * time.Minute, Passive: 40 * time.Minute,
+			Cookware: []string{"oven"}, After: []int{2}},
+		// A dessert which needs the oven too
+		{Recipe: "dessert", Step: 1, Active: 5 * time.Minute, Passive: 20 * time.Minute,
+			Cookware: []string{"oven"}},
+	}
+
+	s, err := Backward(tasks, ready)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(hour int, minute int) time.Time {
+		return time.Date(2026, 10, 20, hour, minute, 0, 0, time.UTC)
+	}
+	want := map[string][2]time.Time{
+		"main 2":    {at(18, 15), at(19, 0)},
+		"main 1":    {at(18, 5), at(18, 15)},
+		"dessert 1": {at(17, 50), at(18, 15)},
+		"sauce 2":   {at(17, 0), at(18, 5)},
+		"sauce 1":   {at(16, 50), at(17, 0)},
+	}
+	if len(s.Entries) != len(want) || !s.Start.Equal(at(16, 50)) {
+		t.Fatalf("Wrong schedule: %+v", s)
+	}
+	for _, entry := range s.Entries {
+		key := fmt.Sprintf("%v %v", entry.Recipe, entry.Step)
+		if span := want[key]; !entry.Start.Equal(span[0]) || !entry.End.Equal(span[1]) {
+			t.Fatalf("Wrong times for %v\ngot: %v - %v\nwant: %v - %v",
+				key, entry.Start, entry.End, span[0], span[1])
+		}
+	}
+
+	// Hands-on work never overlaps
+	for i, a := range s.Entries {
+		for _, b := range s.Entries[i+1:] {
+			hands := interval{a.Start, a.HandsFree}
+			if hands.overlaps(b.Start, b.HandsFree) {
+				t.Fatalf("Hands-on work overlaps: %+v and %+v", a, b)
+			}
+		}
+	}
+
+	tasks = []Task{{Recipe: "a", Step: 1, After: []int{1}}, {Recipe: "b", Step: 1, After: []int{0}}}
+	if _, err = Backward(tasks, ready); !errors.Is(err, ErrCycle) {
+		t.Fatalf("Cycle not detected, got: %v", err)
+	}
+}
+
+func TestParseReady(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"19:00":            time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC),
+		" 7:15 ":           time.Date(2026, 10, 18, 7, 15, 0, 0, time.UTC),
+		"2026-10-20 18:30": time.Date(2026, 10, 20, 18, 30, 0, 0, time.UTC),
+		"2026-10-20T18:30": time.Date(2026, 10, 20, 18, 30, 0, 0, time.UTC),
+	}
+	for text, want := range cases {
+		if got, err := ParseReady(text, now); err != nil || !got.Equal(want) {
+			t.Fatalf("Failed to parse %q\ngot: %v (%v)\nwant: %v", text, got, err, want)
+		}
+	}
+	for _, text := range []string{"", "7pm", "25:00", "2026-13-01 19:00"} {
+		if _, err := ParseReady(text, now); err == nil {
+			t.Fatalf("Parsed invalid ready time %q", text)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package shopping
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+)
+
+// The aisle of items which match no configured aisle
+const OtherAisle = "other"
+
+// The aisles of a store, read from an `aisle.conf` file (as used by CookCLI):
+//
+//	[produce]
+//	potatoes
+//	scallions|green onions
+//
+//	[dairy]
+//	milk
+//
+// Names on the same line are synonyms, merged under the first.
+type Aisles struct {
+	order  []string              // Aisle names, in the order they are configured
+	byName map[string]aisleEntry // Keyed by lowercase ingredient name
+}
+
+// The aisle of an ingredient, and the name its synonyms are merged under
+type aisleEntry struct {
+	Aisle     string
+	Canonical string
+}
+
+// Parses the contents of an `aisle.conf` file.
+//
+// Returns an error for names outside of any section.
+func ParseAisles(text string) (Aisles, error) {
+	aisles := Aisles{order: []string{}, byName: map[string]aisleEntry{}}
+
+	current := ""
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			aisles.order = append(aisles.order, current)
+			continue
+		}
+		if current == "" {
+			return Aisles{}, fmt.Errorf("Line %v: %q is not within an [aisle].", n, line)
+		}
+
+		names := strings.Split(line, "|")
+		canonical := strings.TrimSpace(names[0])
+		for _, name := range names {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if _, ok := aisles.byName[key]; key != "" && !ok {
+				aisles.byName[key] = aisleEntry{Aisle: current, Canonical: canonical}
+			}
+		}
+	}
+	return aisles, scanner.Err()
+}
+
+// Reads the aisles configured at `path`. A missing file configures no aisles.
+func LoadAisles(path string) (Aisles, error) {
+	if !common.FileExists(path) {
+		return Aisles{}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Aisles{}, err
+	}
+
+	var aisles Aisles
+	if aisles, err = ParseAisles(string(data)); err != nil {
+		return Aisles{}, fmt.Errorf("Invalid aisle config %v: %w", path, err)
+	}
+	return aisles, nil
+}
+
+// Returns the aisle of an ingredient (`OtherAisle` if it matches none) and the
+// name to list it under, merging synonyms. Names are matched ignoring case.
+func (a Aisles) Lookup(name string) (string, string) {
+	entry, ok := a.byName[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return OtherAisle, name
+	}
+	return entry.Aisle, entry.Canonical
+}
+
+// Returns the position of an aisle in the store, with unknown aisles (and
+// `OtherAisle`) last.
+func (a Aisles) rank(aisle string) int {
+	for i, name := range a.order {
+		if name == aisle {
+			return i
+		}
+	}
+	return len(a.order)
+}
+
+// Items sharing an aisle
+type AisleGroup struct {
+	Aisle string `json:"aisle"`
+	Items []Item `json:"items"`
+}
+
+// Sorts items by aisle (in the configured order), then by name.
+func (a Aisles) Sort(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		rankI, rankJ := a.rank(items[i].Aisle), a.rank(items[j].Aisle)
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+	})
+}
+
+// Groups items by their aisle, keeping the order of `items` (see `Sort`).
+func GroupByAisle(items []Item) []AisleGroup {
+	groups := make([]AisleGroup, 0)
+	byAisle := map[string]int{}
+	for _, item := range items {
+		aisle := item.Aisle
+		if aisle == "" {
+			aisle = OtherAisle
+		}
+
+		i, ok := byAisle[aisle]
+		if !ok {
+			i = len(groups)
+			byAisle[aisle] = i
+			groups = append(groups, AisleGroup{Aisle: aisle, Items: []Item{}})
+		}
+		groups[i].Items = append(groups[i].Items, item)
+	}
+	return groups
+}
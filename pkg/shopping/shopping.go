@@ -0,0 +1,296 @@
+// Package shopping builds shopping lists from recipes and stores them as TOML
+// files within the shopping directory (see `config.ShoppingConfig`).
+package shopping
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/internal/pkg/common"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+	"github.com/BurntSushi/toml"
+)
+
+// An amount of an item, either a parsed quantity or written text
+// (e.g. "a pinch").
+type Amount struct {
+	Qty  float64 `toml:"qty,omitempty" json:"qty,omitempty"`
+	Text string  `toml:"text,omitempty" json:"text,omitempty"`
+	Unit string  `toml:"unit,omitempty" json:"unit,omitempty"`
+}
+
+// Formats an amount, e.g. "1.5 kg" or "a pinch"
+func (a Amount) String() string {
+	qty := a.Text
+	if a.Qty != cook.NoQty {
+		qty = cook.FormatQty(a.Qty)
+	}
+	return strings.TrimSpace(qty + " " + a.Unit)
+}
+
+// An item to buy, with every amount needed of it. Amounts in units which
+// can't be converted between (e.g. "2 cups" and "100 g") are kept apart.
+type Item struct {
+	ID      int      `toml:"id" json:"id"` // Unique within its list, never reused
+	Name    string   `toml:"name" json:"name"`
+	Aisle   string   `toml:"aisle" json:"aisle"`
+	Amounts []Amount `toml:"amounts" json:"amounts"`
+	Checked bool     `toml:"checked" json:"checked"`
+}
+
+// Formats the amounts of an item, e.g. "2 cups, 100 g"
+func (item Item) AmountText() string {
+	parts := make([]string, 0, len(item.Amounts))
+	for _, amount := range item.Amounts {
+		if text := amount.String(); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// A shopping list, along with the recipes it was built from
+type List struct {
+	Name    string   `toml:"name" json:"name"`
+	Recipes []string `toml:"recipes" json:"recipes"` // e.g. "pancakes:4"
+	Items   []Item   `toml:"items" json:"items"`
+	NextID  int      `toml:"next-id" json:"-"` // The ID of the next item added
+}
+
+var (
+	ErrListNotFound = errors.New("Shopping list not found")
+	ErrItemNotFound = errors.New("Shopping list item not found.")
+	// Returned for malformed list names and recipe arguments
+	ErrInvalidListName = errors.New("Invalid list name")
+	ErrInvalidServings = errors.New("Invalid servings")
+)
+
+// Returns the index of the item with `id`, -1 if there is none
+func (l *List) index(id int) int {
+	for i, item := range l.Items {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Adds an item to the end of the list, giving it the next free ID.
+//
+// Returns the item as added.
+func (l *List) AddItem(item Item) Item {
+	for _, existing := range l.Items {
+		if existing.ID >= l.NextID {
+			l.NextID = existing.ID + 1
+		}
+	}
+	if l.NextID < 1 {
+		l.NextID = 1
+	}
+
+	item.ID = l.NextID
+	l.NextID++
+	if item.Amounts == nil {
+		item.Amounts = []Amount{}
+	}
+	l.Items = append(l.Items, item)
+	return item
+}
+
+// Checks (or unchecks) the item with `id`.
+//
+// Returns ErrItemNotFound if the list has no such item.
+func (l *List) SetChecked(id int, checked bool) error {
+	i := l.index(id)
+	if i < 0 {
+		return ErrItemNotFound
+	}
+	l.Items[i].Checked = checked
+	return nil
+}
+
+// Removes the item with `id` from the list.
+//
+// Returns ErrItemNotFound if the list has no such item.
+func (l *List) RemoveItem(id int) error {
+	i := l.index(id)
+	if i < 0 {
+		return ErrItemNotFound
+	}
+	l.Items = append(l.Items[:i], l.Items[i+1:]...)
+	return nil
+}
+
+// Matches the names lists may be saved as
+var listNameRegex = regexp.MustCompile(`^[\w\- ]+$`)
+
+// Splits a recipe argument into its name and servings, e.g.
+// "breakfast/pancakes:4" -> ("breakfast/pancakes", 4).
+//
+// Servings are 0 if left out.
+func ParseRecipeArg(arg string) (string, float64, error) {
+	i := strings.LastIndex(arg, ":")
+	if i < 0 {
+		return strings.TrimSuffix(arg, ".cook"), 0, nil
+	}
+
+	servings, err := strconv.ParseFloat(arg[i+1:], 64)
+	if err != nil || servings <= 0 {
+		return "", 0, fmt.Errorf("%w in %q, expected e.g. recipe:4", ErrInvalidServings, arg)
+	}
+	return strings.TrimSuffix(arg[:i], ".cook"), servings, nil
+}
+
+// Adds an amount to an item, summing it into an existing amount of a
+// compatible unit where possible.
+func (item *Item) add(amount Amount) {
+	for i, existing := range item.Amounts {
+		if amount.Qty == cook.NoQty || existing.Qty == cook.NoQty {
+			// Written amounts are only merged with duplicates
+			if amount == existing {
+				return
+			}
+			continue
+		}
+
+		if qty, err := units.Convert(amount.Qty, amount.Unit, existing.Unit); err == nil {
+			item.Amounts[i].Qty += qty
+			return
+		}
+	}
+
+	if amount != (Amount{}) {
+		item.Amounts = append(item.Amounts, amount)
+	}
+}
+
+// Combines ingredients into a list of items, sorted by aisle then name (and
+// numbered in that order). Ingredients of the same name (ignoring case) or
+// synonyms in `aisles` are merged, summing quantities across compatible units.
+//
+// Summed quantities are shown in the most readable unit of `system`
+// (`units.Metric` or `units.Imperial`), they are left in their unit if
+// `system` is empty.
+func Combine(ingredients []cook.Ingredient, system string, aisles Aisles) []Item {
+	items := make([]Item, 0)
+	byName := map[string]int{}
+	for _, ingr := range ingredients {
+		if strings.TrimSpace(ingr.Name) == "" || ingr.IsRecipeRef() {
+			continue
+		}
+		aisle, name := aisles.Lookup(strings.TrimSpace(ingr.Name))
+		key := strings.ToLower(name)
+
+		i, ok := byName[key]
+		if !ok {
+			i = len(items)
+			byName[key] = i
+			items = append(items, Item{Name: name, Aisle: aisle, Amounts: []Amount{}})
+		}
+
+		amount := Amount{Qty: ingr.QtyVal, Unit: ingr.Unit}
+		if ingr.QtyVal == cook.NoQty {
+			amount.Text = ingr.Qty
+		}
+		items[i].add(amount)
+	}
+
+	for _, item := range items {
+		for j, amount := range item.Amounts {
+			if amount.Qty != cook.NoQty && system != "" {
+				item.Amounts[j].Qty, item.Amounts[j].Unit = units.Humanize(amount.Qty, amount.Unit, system)
+			}
+		}
+	}
+
+	aisles.Sort(items)
+	for i := range items {
+		items[i].ID = i + 1
+	}
+	return items
+}
+
+// Returns the path of the list named `name` within `dir`.
+//
+// Returns an error if the name isn't a valid list name (letters, digits,
+// spaces, `-` and `_`).
+func ListPath(dir string, name string) (string, error) {
+	if !listNameRegex.MatchString(name) {
+		return "", fmt.Errorf("%w %q, use letters, digits, spaces, - and _", ErrInvalidListName, name)
+	}
+	return filepath.Join(dir, name+".toml"), nil
+}
+
+// Writes a list into `dir`, as `<name>.toml`. The file is written to a
+// temporary file first, so readers never see a partial list.
+func Save(dir string, list List) error {
+	var err error
+	var listPath string
+	if listPath, err = ListPath(dir, list.Name); err != nil {
+		return err
+	}
+
+	var file *os.File
+	if file, err = os.CreateTemp(dir, "."+list.Name+"-*.tmp"); err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err = toml.NewEncoder(file).Encode(list); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), listPath)
+}
+
+// Reads the list named `name` from `dir`.
+//
+// Returns ErrListNotFound if there is no such list.
+func Load(dir string, name string) (List, error) {
+	var err error
+	var listPath string
+	if listPath, err = ListPath(dir, name); err != nil {
+		return List{}, err
+	}
+	if !common.FileExists(listPath) {
+		return List{}, fmt.Errorf("%w: %v", ErrListNotFound, name)
+	}
+
+	var list List
+	if _, err = toml.DecodeFile(listPath, &list); err != nil {
+		return List{}, err
+	}
+	list.Name = name
+	if list.Items == nil {
+		list.Items = []Item{}
+	}
+	return list, nil
+}
+
+// Returns the names of every list within `dir`, sorted.
+func Names(dir string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), ".toml")
+		if listNameRegex.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
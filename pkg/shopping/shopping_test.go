@@ -0,0 +1,92 @@
+package shopping
+
+import (
+	"testing"
+
+	"git.sr.ht/~rottenfishbone/go-cook"
+	"git.sr.ht/~rottenfishbone/go-cook/pkg/units"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestParseRecipeArg(t *testing.T) {
+	testParse := func(arg string, wantName string, wantServings float64) {
+		name, servings, err := ParseRecipeArg(arg)
+		if err != nil || name != wantName || servings != wantServings {
+			t.Fatalf("Failed to parse %q\ngot: %v, %v (%v)\nwant: %v, %v.",
+				arg, name, servings, err, wantName, wantServings)
+		}
+	}
+
+	testParse("breakfast/pancakes:4", "breakfast/pancakes", 4)
+	testParse("toast.cook", "toast", 0)
+	if _, _, err := ParseRecipeArg("toast:many"); err == nil {
+		t.Fatalf("Parsed invalid servings")
+	}
+}
+
+func TestCombine(t *testing.T) {
+	r := cook.ParseRecipeString("", "@flour{500%g} @Flour{1%kg} @flour{1%cup} "+
+		"@salt{a pinch} @salt{a pinch} @milk{250%ml} @milk{0.5%l} @eggs{2} @eggs{3} @pepper")
+
+	testCombine := func(system string, want map[string]string) {
+		items := Combine(r.Ingredients, system, Aisles{})
+		if len(items) != len(want) {
+			t.Fatalf("Wrong items, got: %+v", items)
+		}
+		for _, item := range items {
+			if got := item.AmountText(); got != want[item.Name] {
+				t.Fatalf("Wrong amount of %v\ngot: %q\nwant: %q.", item.Name, got, want[item.Name])
+			}
+		}
+	}
+
+	testCombine("", map[string]string{
+		"eggs":   "5",
+		"flour":  "1500 g, 1 cup",
+		"milk":   "750 ml",
+		"pepper": "",
+		"salt":   "a pinch",
+	})
+	testCombine(units.Metric, map[string]string{
+		"eggs":   "5",
+		"flour":  "1.5 kg, 236.59 ml",
+		"milk":   "750 ml",
+		"pepper": "",
+		"salt":   "a pinch",
+	})
+}
+
+func TestAisles(t *testing.T) {
+	aisles, err := ParseAisles("[produce]\nscallions|green onion\ntomatoes\n\n[dairy]\nmilk\n")
+	if err != nil {
+		t.Fatalf("Failed to parse aisles: %v", err)
+	}
+	if _, err = ParseAisles("milk\n[dairy]"); err == nil {
+		t.Fatalf("Parsed a name outside of any aisle")
+	}
+
+	r := cook.ParseRecipeString("", "@Green Onion{2} @milk{1%l} @scallions{1} @salt @tomatoes{3}")
+	groups := GroupByAisle(Combine(r.Ingredients, "", aisles))
+
+	want := []AisleGroup{
+		{"produce", []Item{{Name: "scallions", Amounts: []Amount{{Qty: 3}}}, {Name: "tomatoes", Amounts: []Amount{{Qty: 3}}}}},
+		{"dairy", []Item{{Name: "milk", Amounts: []Amount{{Qty: 1, Unit: "l"}}}}},
+		{OtherAisle, []Item{{Name: "salt", Amounts: []Amount{}}}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("Wrong groups, got: %+v", groups)
+	}
+	for i, group := range groups {
+		if group.Aisle != want[i].Aisle || len(group.Items) != len(want[i].Items) {
+			t.Fatalf("Wrong group\ngot: %+v\nwant: %+v.", group, want[i])
+		}
+		for j, item := range group.Items {
+			if item.Name != want[i].Items[j].Name || item.AmountText() != want[i].Items[j].AmountText() {
+				t.Fatalf("Wrong item in %v\ngot: %+v\nwant: %+v.", group.Aisle, item, want[i].Items[j])
+			}
+		}
+	}
+}
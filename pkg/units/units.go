@@ -0,0 +1,266 @@
+// Package units provides lookup and conversion of the units commonly found in
+// recipes (e.g. "g", "cups", "tbsp", "minutes").
+package units
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The physical quantity a unit measures. Units can only be converted between
+// others of the same dimension.
+type Dimension string
+
+const (
+	Mass   Dimension = "mass"
+	Volume Dimension = "volume"
+	Length Dimension = "length"
+	Time   Dimension = "time"
+	// Named units (e.g. "clove", "pinch") which only convert to themselves
+	Other Dimension = "other"
+)
+
+// Unit systems, as used by the `units` config setting
+const (
+	Metric   = "metric"
+	Imperial = "imperial"
+)
+
+// A known unit of measurement
+type Unit struct {
+	Name      string    // Canonical name, e.g. "g"
+	Dimension Dimension // What the unit measures
+	Factor    float64   // Size in the dimension's base unit (g, ml, mm, s)
+	System    string    // Metric, Imperial or "" for units used in both
+}
+
+var ErrIncompatible = errors.New("Units are not compatible.")
+
+// Every known unit, keyed by canonical name
+var unitTable = map[string]Unit{}
+
+// Maps each accepted spelling (lowercase) of a unit to its canonical name
+var aliasTable = map[string]string{}
+
+// Adds a unit and all of its aliases to the tables
+func define(unit Unit, aliases ...string) {
+	unitTable[unit.Name] = unit
+	aliasTable[strings.ToLower(unit.Name)] = unit.Name
+	for _, alias := range aliases {
+		aliasTable[alias] = unit.Name
+	}
+}
+
+func init() {
+	// Mass (base: grams)
+	define(Unit{"mg", Mass, 0.001, Metric}, "milligram", "milligrams")
+	define(Unit{"g", Mass, 1, Metric}, "gr", "gram", "grams", "gramme", "grammes")
+	define(Unit{"kg", Mass, 1000, Metric}, "kgs", "kilo", "kilos", "kilogram", "kilograms")
+	define(Unit{"oz", Mass, 28.349523125, Imperial}, "ounce", "ounces")
+	define(Unit{"lb", Mass, 453.59237, Imperial}, "lbs", "pound", "pounds")
+
+	// Volume (base: millilitres)
+	define(Unit{"ml", Volume, 1, Metric},
+		"milliliter", "milliliters", "millilitre", "millilitres")
+	define(Unit{"cl", Volume, 10, Metric},
+		"centiliter", "centiliters", "centilitre", "centilitres")
+	define(Unit{"dl", Volume, 100, Metric},
+		"deciliter", "deciliters", "decilitre", "decilitres")
+	define(Unit{"l", Volume, 1000, Metric}, "liter", "liters", "litre", "litres")
+	define(Unit{"tsp", Volume, 4.92892159375, Imperial}, "tsps", "teaspoon", "teaspoons")
+	define(Unit{"tbsp", Volume, 14.78676478125, Imperial},
+		"tbsps", "tbs", "tbl", "tablespoon", "tablespoons")
+	define(Unit{"fl oz", Volume, 29.5735295625, Imperial},
+		"fl. oz", "floz", "fluid ounce", "fluid ounces")
+	define(Unit{"cup", Volume, 236.5882365, Imperial}, "cups", "c")
+	define(Unit{"pint", Volume, 473.176473, Imperial}, "pints", "pt")
+	define(Unit{"quart", Volume, 946.352946, Imperial}, "quarts", "qt")
+	define(Unit{"gallon", Volume, 3785.411784, Imperial}, "gallons", "gal")
+
+	// Length (base: millimetres)
+	define(Unit{"mm", Length, 1, Metric}, "millimeter", "millimeters", "millimetre", "millimetres")
+	define(Unit{"cm", Length, 10, Metric}, "centimeter", "centimeters", "centimetre", "centimetres")
+	define(Unit{"in", Length, 25.4, Imperial}, "inch", "inches", `"`)
+
+	// Time (base: seconds)
+	define(Unit{"s", Time, 1, ""}, "sec", "secs", "second", "seconds")
+	define(Unit{"min", Time, 60, ""}, "mins", "minute", "minutes")
+	define(Unit{"h", Time, 3600, ""}, "hr", "hrs", "hour", "hours")
+	define(Unit{"day", Time, 86400, ""}, "days")
+
+	// Named units, these only convert to themselves
+	named := [][]string{
+		{"pinch", "pinches"}, {"dash", "dashes"}, {"drop", "drops"},
+		{"clove", "cloves"}, {"slice", "slices"}, {"piece", "pieces", "pc", "pcs"},
+		{"can", "cans", "tin", "tins"}, {"jar", "jars"}, {"bottle", "bottles"},
+		{"package", "packages", "pack", "packs", "packet", "packets"},
+		{"bunch", "bunches"}, {"handful", "handfuls"}, {"sprig", "sprigs"},
+		{"stick", "sticks"}, {"leaf", "leaves"}, {"head", "heads"},
+		{"stalk", "stalks"}, {"serving", "servings"},
+	}
+	for _, names := range named {
+		define(Unit{names[0], Other, 1, ""}, names[1:]...)
+	}
+}
+
+// Normalizes how a unit is written, for alias lookups
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.TrimSuffix(name, ".")
+}
+
+// Finds a unit by any of its accepted spellings (case insensitive).
+//
+// e.g. "Grams", "g" and "gram" all return the "g" unit
+func Lookup(name string) (Unit, bool) {
+	canon, ok := aliasTable[normalize(name)]
+	if !ok {
+		return Unit{}, false
+	}
+	return unitTable[canon], true
+}
+
+// Tests if `name` is a known unit
+func Known(name string) bool {
+	_, ok := Lookup(name)
+	return ok
+}
+
+// Tests if quantities in unit `a` can be converted to unit `b`.
+//
+// Unknown units are only compatible with differently written versions of
+// themselves, e.g. "knob" and "Knob".
+func Compatible(a string, b string) bool {
+	unitA, okA := Lookup(a)
+	unitB, okB := Lookup(b)
+	if !okA || !okB {
+		return !okA && !okB && normalize(a) == normalize(b)
+	}
+
+	if unitA.Dimension == Other || unitB.Dimension == Other {
+		return unitA.Name == unitB.Name
+	}
+	return unitA.Dimension == unitB.Dimension
+}
+
+// Converts `val` from unit `from` into unit `to`.
+//
+// Returns ErrIncompatible if the units measure different things (or are unknown).
+func Convert(val float64, from string, to string) (float64, error) {
+	if !Compatible(from, to) {
+		return 0, ErrIncompatible
+	}
+
+	unitFrom, okFrom := Lookup(from)
+	unitTo, okTo := Lookup(to)
+	if !okFrom || !okTo {
+		// Same unknown unit
+		return val, nil
+	}
+	return val * unitFrom.Factor / unitTo.Factor, nil
+}
+
+// Units of each dimension ordered small to large, per system. Used to find a
+// readable unit for a quantity.
+var ladders = map[string]map[Dimension][]string{
+	Metric: {
+		Mass:   {"mg", "g", "kg"},
+		Volume: {"ml", "l"},
+		Length: {"mm", "cm"},
+		Time:   {"s", "min", "h"},
+	},
+	Imperial: {
+		Mass:   {"oz", "lb"},
+		Volume: {"tsp", "tbsp", "cup", "quart", "gallon"},
+		Length: {"in"},
+		Time:   {"s", "min", "h"},
+	},
+}
+
+// Converts a quantity to the most readable unit of the same dimension in
+// `system` (Metric or Imperial). The largest unit that keeps the value >= 1 is
+// chosen.
+//
+// e.g. (1500, "g", Metric) -> (1.5, "kg"), (3, "tsp", Imperial) -> (1, "tbsp")
+//
+// Unknown units and units without an equivalent in `system` are returned as is.
+func Humanize(val float64, unit string, system string) (float64, string) {
+	from, ok := Lookup(unit)
+	if !ok {
+		return val, unit
+	}
+
+	ladder, ok := ladders[system][from.Dimension]
+	if !ok {
+		return val, unit
+	}
+
+	best := ladder[0]
+	for _, name := range ladder {
+		if converted, _ := Convert(val, from.Name, name); converted >= 1 {
+			best = name
+		}
+	}
+
+	converted, _ := Convert(val, from.Name, best)
+	return converted, best
+}
+
+// Converts a quantity of a time unit into a `time.Duration`.
+//
+// e.g. (25, "minutes") -> 25m0s
+func ToDuration(val float64, unit string) (time.Duration, error) {
+	u, ok := Lookup(unit)
+	if !ok || u.Dimension != Time {
+		return 0, fmt.Errorf("Not a unit of time: %q", unit)
+	}
+	return time.Duration(val * u.Factor * float64(time.Second)), nil
+}
+
+// Matches each "<number> <unit>" pair within a written duration
+var durationPartRegex = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*([a-zA-Z]+\.?)`)
+
+// Parses a written duration, as commonly found in recipe metadata.
+//
+// e.g. "45 minutes", "1 hour 30 mins", "1h30m" and "1.5 hrs"
+//
+// A lone number is read as minutes. Returns an error if any part is not a
+// quantity of a unit of time.
+func ParseDuration(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	if val, err := strconv.ParseFloat(text, 64); err == nil {
+		return ToDuration(val, "min")
+	}
+	if d, err := time.ParseDuration(text); err == nil {
+		return d, nil
+	}
+
+	matches := durationPartRegex.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return 0, fmt.Errorf("Not a duration: %q", text)
+	}
+
+	var total time.Duration
+	rest := text
+	for _, match := range matches {
+		val, _ := strconv.ParseFloat(strings.Replace(text[match[2]:match[3]], ",", ".", 1), 64)
+		d, err := ToDuration(val, text[match[4]:match[5]])
+		if err != nil {
+			return 0, err
+		}
+		total += d
+		rest = strings.Replace(rest, text[match[0]:match[1]], "", 1)
+	}
+
+	// Anything left, other than joining words, isn't part of a duration
+	for _, word := range strings.Fields(rest) {
+		if word != "and" && word != "," {
+			return 0, fmt.Errorf("Not a duration: %q", text)
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,83 @@
+package units
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestConvert(t *testing.T) {
+	testConvert := func(val float64, from string, to string, want float64) {
+		got, err := Convert(val, from, to)
+		if err != nil || math.Abs(got-want) > 0.01 {
+			t.Fatalf("Failed to convert %v %v to %v\ngot: %v (%v)\nwant: %v.",
+				val, from, to, got, err, want)
+		}
+	}
+
+	testConvert(1500, "g", "kg", 1.5)
+	testConvert(1, "Cups", "ml", 236.59)
+	testConvert(3, "tsp", "tablespoon", 1)
+	testConvert(2, "cloves", "clove", 2)
+	testConvert(1, "knob", "Knob", 1)
+
+	// Should fail
+	if _, err := Convert(1, "g", "ml"); err != ErrIncompatible {
+		t.Fatalf("Converted mass to volume")
+	}
+	if _, err := Convert(1, "clove", "pinch"); err != ErrIncompatible {
+		t.Fatalf("Converted between named units")
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	testHumanize := func(val float64, unit string, system string, want float64, wantUnit string) {
+		got, gotUnit := Humanize(val, unit, system)
+		if math.Abs(got-want) > 0.01 || gotUnit != wantUnit {
+			t.Fatalf("Failed to humanize %v %v\ngot: %v %v\nwant: %v %v.",
+				val, unit, got, gotUnit, want, wantUnit)
+		}
+	}
+
+	testHumanize(1500, "g", Metric, 1.5, "kg")
+	testHumanize(250, "ml", Metric, 250, "ml")
+	testHumanize(6, "tsp", Imperial, 2, "tbsp")
+	testHumanize(454, "g", Imperial, 1, "lb")
+	testHumanize(3, "pinch", Metric, 3, "pinch")
+}
+
+func TestToDuration(t *testing.T) {
+	if d, err := ToDuration(1.5, "hours"); err != nil || d != 90*time.Minute {
+		t.Fatalf("Failed to convert 1.5 hours: %v (%v)", d, err)
+	}
+	if _, err := ToDuration(1, "g"); err == nil {
+		t.Fatalf("Converted grams into a duration")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	testParseDuration := func(text string, want time.Duration) {
+		got, err := ParseDuration(text)
+		if err != nil || got != want {
+			t.Fatalf("Failed to parse duration %q\ngot: %v (%v)\nwant: %v.", text, got, err, want)
+		}
+	}
+
+	testParseDuration("45 minutes", 45*time.Minute)
+	testParseDuration("1 hour 30 mins", 90*time.Minute)
+	testParseDuration("1h30m", 90*time.Minute)
+	testParseDuration("1.5 hrs", 90*time.Minute)
+	testParseDuration("2 hours and 5 minutes", 125*time.Minute)
+	testParseDuration("20", 20*time.Minute)
+
+	// Should fail
+	for _, text := range []string{"", "overnight", "2 cups", "about 20 minutes"} {
+		if _, err := ParseDuration(text); err == nil {
+			t.Fatalf("Parsed %q as a duration", text)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cook
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Formats a quantity value as a short string, rounded to two decimal places.
+//
+// e.g. 1.5 -> "1.5", 0.3333 -> "0.33", 2 -> "2"
+func FormatQty(val float64) string {
+	return strconv.FormatFloat(math.Round(val*100)/100, 'f', -1, 64)
+}
+
+// Multiplies the quantity of every ingredient in the recipe (manifest and steps)
+// by `factor`. Unparsable quantities (e.g. "a pinch") are left as is.
+//
+// Cookware and timers are not scaled.
+func ScaleRecipe(r *Recipe, factor float64) {
+	scale := func(ingr Ingredient) Ingredient {
+		if ingr.QtyVal == NoQty {
+			return ingr
+		}
+		ingr.QtyVal *= factor
+		ingr.Qty = FormatQty(ingr.QtyVal)
+		return ingr
+	}
+
+	for i, ingr := range r.Ingredients {
+		r.Ingredients[i] = scale(ingr)
+	}
+	for _, step := range r.Steps {
+		for j, chunk := range step {
+			if ingr, ok := chunk.(Ingredient); ok {
+				step[j] = scale(ingr)
+			}
+		}
+	}
+}
+
+// Returns the number of servings a recipe makes, as defined by its `servings`
+// metadata. The bool is false if it is missing or unparsable.
+//
+// Only the first value is used for multi-valued servings. e.g. "2|4|6" -> 2
+func RecipeServings(r *Recipe) (float64, bool) {
+	servings, ok := r.Metadata.Get("servings")
+	if !ok {
+		return NoQty, false
+	}
+
+	servings = strings.TrimSpace(strings.Split(servings, "|")[0])
+	val := TryParseQty(servings)
+	return val, val != NoQty
+}
+
+// Returns the factor a referenced recipe should be scaled by, given the
+// `ref` ingredient used to include it and the referenced recipe `sub`.
+//
+//   - No (or an unparsable) quantity uses the recipe as is, e.g. `{}`
+//   - Unitless quantities are multipliers, e.g. `{2}` doubles the recipe
+//   - "serving(s)" are scaled against the sub-recipe's servings, e.g.
+//     `{2%servings}` of a recipe which serves 4 halves it
+//
+// Any other unit can't be related to the sub-recipe, as recipes don't state
+// their yield by weight or volume, so it is used as is: e.g. `{150%g}` of a
+// sauce includes the whole sauce recipe.
+func RecipeRefScale(ref Ingredient, sub *Recipe) float64 {
+	if ref.QtyVal == NoQty {
+		return 1
+	}
+
+	switch strings.ToLower(ref.Unit) {
+	case "", "x":
+		return ref.QtyVal
+	case "serving", "servings":
+		if servings, ok := RecipeServings(sub); ok {
+			return ref.QtyVal / servings
+		}
+		return ref.QtyVal
+	default:
+		return 1
+	}
+}
+
+// Merges ingredients of the same name and unit by summing their quantities,
+// keeping the order they first appear in.
+//
+// Ingredients with unparsable quantities are only merged with exact duplicates.
+func CombineIngredients(ingredients []Ingredient) []Ingredient {
+	combined := make([]Ingredient, 0, len(ingredients))
+	for _, ingr := range ingredients {
+		merged := false
+		for i, existing := range combined {
+			if !strings.EqualFold(existing.Name, ingr.Name) ||
+				!strings.EqualFold(existing.Unit, ingr.Unit) {
+				continue
+			}
+
+			if existing.QtyVal != NoQty && ingr.QtyVal != NoQty {
+				combined[i].QtyVal += ingr.QtyVal
+				combined[i].Qty = FormatQty(combined[i].QtyVal)
+				merged = true
+			} else if existing.Qty == ingr.Qty {
+				merged = true
+			}
+
+			if merged {
+				break
+			}
+		}
+
+		if !merged {
+			combined = append(combined, ingr)
+		}
+	}
+
+	return combined
+}
@@ -0,0 +1,62 @@
+package cook
+
+import (
+	"reflect"
+	"testing"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestScaleRecipe(t *testing.T) {
+	r := ParseRecipeString("", "Mix @flour{125%g}, @eggs{3} and @salt{a pinch}")
+	ScaleRecipe(&r, 2)
+
+	want := []Ingredient{
+		{Name: "flour", Qty: "250", QtyVal: 250, Unit: "g"},
+		{Name: "eggs", Qty: "6", QtyVal: 6, Unit: ""},
+		{Name: "salt", Qty: "a pinch", QtyVal: NoQty, Unit: ""},
+	}
+	if !reflect.DeepEqual(r.Ingredients, want) {
+		t.Fatalf("Failed to scale manifest:\ngot: %+v\nwant: %+v.", r.Ingredients, want)
+	}
+	if r.Steps[0][1] != Ingredient(want[0]) {
+		t.Fatalf("Failed to scale step:\ngot: %+v\nwant: %+v.", r.Steps[0][1], want[0])
+	}
+}
+
+func TestRecipeRefScale(t *testing.T) {
+	sub := ParseRecipeString("", ">> servings: 4|8\nStep")
+	testScale := func(ref Ingredient, want float64) {
+		if got := RecipeRefScale(ref, &sub); got != want {
+			t.Fatalf("Wrong scale for %+v\ngot: %v\nwant: %v.", ref, got, want)
+		}
+	}
+
+	testScale(Ingredient{Qty: "", QtyVal: NoQty}, 1)
+	testScale(Ingredient{Qty: "2", QtyVal: 2}, 2)
+	testScale(Ingredient{Qty: "2", QtyVal: 2, Unit: "servings"}, 0.5)
+	testScale(Ingredient{Qty: "150", QtyVal: 150, Unit: "g"}, 1)
+}
+
+func TestCombineIngredients(t *testing.T) {
+	got := CombineIngredients([]Ingredient{
+		{Name: "flour", Qty: "100", QtyVal: 100, Unit: "g"},
+		{Name: "eggs", Qty: "2", QtyVal: 2},
+		{Name: "Flour", Qty: "50", QtyVal: 50, Unit: "g"},
+		{Name: "salt", Qty: "a pinch", QtyVal: NoQty},
+		{Name: "salt", Qty: "a pinch", QtyVal: NoQty},
+		{Name: "flour", Qty: "1", QtyVal: 1, Unit: "cup"},
+	})
+
+	want := []Ingredient{
+		{Name: "flour", Qty: "150", QtyVal: 150, Unit: "g"},
+		{Name: "eggs", Qty: "2", QtyVal: 2},
+		{Name: "salt", Qty: "a pinch", QtyVal: NoQty},
+		{Name: "flour", Qty: "1", QtyVal: 1, Unit: "cup"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Failed to combine:\ngot: %+v\nwant: %+v.", got, want)
+	}
+}
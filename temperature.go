@@ -0,0 +1,219 @@
+package cook
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The scale a `Temperature` was written in
+type TempScale string
+
+const (
+	Celsius    TempScale = "C"
+	Fahrenheit TempScale = "F"
+	GasMark    TempScale = "gas mark"
+)
+
+// Temperature is a typed chunk created from temperatures found within `Text`.
+//
+// Temperatures are not part of the cooklang spec, they are only produced by
+// the optional post-processing pass `ParseTemperatures`.
+type Temperature struct {
+	Value float64   `json:"value"`
+	Scale TempScale `json:"scale"`
+	Raw   string    `json:"raw"` // The text the temperature was parsed from
+}
+
+func (Temperature) isChunk() {}
+
+// Converts a temperature back to the text it was parsed from
+func (x Temperature) ToString() string {
+	return x.Raw
+}
+
+// Gas marks mapped to their (approximate) oven temperatures in celsius.
+//
+// Sorted ascending, so the table can be used for nearest-neighbour lookups.
+var gasMarks = []struct {
+	mark    float64
+	celsius float64
+}{
+	{0.25, 110}, {0.5, 120}, {1, 140}, {2, 150}, {3, 170}, {4, 180},
+	{5, 190}, {6, 200}, {7, 220}, {8, 230}, {9, 240}, {10, 260},
+}
+
+// Returns the temperature in degrees celsius
+func (x Temperature) Celsius() float64 {
+	switch x.Scale {
+	case Fahrenheit:
+		return (x.Value - 32) * 5 / 9
+	case GasMark:
+		// Use the closest mark for values not in the table
+		best := gasMarks[0]
+		for _, gm := range gasMarks {
+			if math.Abs(gm.mark-x.Value) < math.Abs(best.mark-x.Value) {
+				best = gm
+			}
+		}
+		return best.celsius
+	default:
+		return x.Value
+	}
+}
+
+// Returns the temperature in degrees fahrenheit
+func (x Temperature) Fahrenheit() float64 {
+	if x.Scale == Fahrenheit {
+		return x.Value
+	}
+	return x.Celsius()*9/5 + 32
+}
+
+// Returns the gas mark closest to the temperature
+func (x Temperature) GasMark() float64 {
+	if x.Scale == GasMark {
+		return x.Value
+	}
+	c := x.Celsius()
+	best := gasMarks[0]
+	for _, gm := range gasMarks {
+		if math.Abs(gm.celsius-c) < math.Abs(best.celsius-c) {
+			best = gm
+		}
+	}
+	return best.mark
+}
+
+// Converts a temperature into another scale, `Raw` is rebuilt to match.
+//
+// Celsius and fahrenheit are rounded to the nearest degree.
+func (x Temperature) Convert(scale TempScale) Temperature {
+	if scale == x.Scale {
+		return x
+	}
+
+	var val float64
+	switch scale {
+	case Celsius:
+		val = math.Round(x.Celsius())
+	case Fahrenheit:
+		val = math.Round(x.Fahrenheit())
+	case GasMark:
+		val = x.GasMark()
+	default:
+		panic("Tried to convert to unhandled TempScale")
+	}
+
+	t := Temperature{Value: val, Scale: scale}
+	t.Raw = t.format()
+	return t
+}
+
+// Formats a temperature as a human readable string. e.g. "200°C" or "gas mark 6"
+func (x Temperature) format() string {
+	val := strconv.FormatFloat(x.Value, 'f', -1, 64)
+	if x.Scale == GasMark {
+		return "gas mark " + val
+	}
+	return fmt.Sprintf("%v°%v", val, x.Scale)
+}
+
+// Matches temperatures such as "200°C", "400 °f", "180 degrees celsius" and
+// "gas mark 6".
+//
+// Scale letters must follow a degree sign or "degrees", as bare letters (e.g.
+// "2C water") are more likely to be shorthand for cups.
+var tempRegex = regexp.MustCompile(
+	`\b(?:(\d+(?:\.\d+)?)(?:\s?[°º]\s?(?i:([CF]))|\s(?i:degrees?)\s(?i:(celsius|fahrenheit|c|f)))\b|` +
+		`(?i:gas\s?mark)\s?(\d+(?:/\d+)?\b|[¼½]))`)
+
+// Builds a `Temperature` from a submatch of `tempRegex`. The bool is false if
+// the match could not be parsed.
+func temperatureFromMatch(match []string) (Temperature, bool) {
+	t := Temperature{Raw: match[0]}
+
+	// Gas marks
+	if match[4] != "" {
+		switch match[4] {
+		case "¼":
+			t.Value = 0.25
+		case "½":
+			t.Value = 0.5
+		default:
+			t.Value = TryParseQty(match[4])
+		}
+		t.Scale = GasMark
+		return t, t.Value != NoQty
+	}
+
+	val, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return t, false
+	}
+	t.Value = val
+
+	scale := strings.ToUpper(match[2] + match[3])
+	if strings.HasPrefix(scale, "F") {
+		t.Scale = Fahrenheit
+	} else {
+		t.Scale = Celsius
+	}
+
+	return t, true
+}
+
+// Splits a `Text` chunk into `Text` and `Temperature` chunks.
+func splitTemperatures(text Text) []Chunk {
+	str := string(text)
+	chunks := make([]Chunk, 0, 1)
+
+	last := 0
+	for _, idx := range tempRegex.FindAllStringSubmatchIndex(str, -1) {
+		// Rebuild the submatches from their indices
+		match := make([]string, len(idx)/2)
+		for i := range match {
+			if idx[2*i] >= 0 {
+				match[i] = str[idx[2*i]:idx[2*i+1]]
+			}
+		}
+
+		temp, ok := temperatureFromMatch(match)
+		if !ok {
+			continue
+		}
+
+		if idx[0] > last {
+			chunks = append(chunks, Text(str[last:idx[0]]))
+		}
+		chunks = append(chunks, temp)
+		last = idx[1]
+	}
+
+	if last < len(str) {
+		chunks = append(chunks, Text(str[last:]))
+	}
+	return chunks
+}
+
+// A post-processing pass that detects temperatures in the `Text` chunks of
+// each step and converts them into `Temperature` chunks.
+//
+// e.g. `Text("Heat oven up to 200°C")` becomes
+// `[Text("Heat oven up to "), Temperature{200, "C", "200°C"}]`
+func ParseTemperatures(r *Recipe) {
+	for i, step := range r.Steps {
+		newStep := make(Step, 0, len(step))
+		for _, chunk := range step {
+			text, ok := chunk.(Text)
+			if !ok {
+				newStep = append(newStep, chunk)
+				continue
+			}
+			newStep = append(newStep, splitTemperatures(text)...)
+		}
+		r.Steps[i] = newStep
+	}
+}
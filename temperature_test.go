@@ -0,0 +1,88 @@
+package cook
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// --------------------------------------------------------------
+// Unit Tests
+// --------------------------------------------------------------
+
+func TestParseTemperatures(t *testing.T) {
+	// Function to easily test inputs
+	testTemps := func(in string, want Step) {
+		r := ParseRecipeString("", in)
+		ParseTemperatures(&r)
+		if len(r.Steps) != 1 || !reflect.DeepEqual(r.Steps[0], want) {
+			t.Fatalf("Failed to parse: \"%s\"\ngot: %#v\nwant: %#v.", in, r.Steps, want)
+		}
+	}
+
+	// Should convert
+	testTemps("Heat oven up to 200°C", Step{
+		Text("Heat oven up to "),
+		Temperature{Value: 200, Scale: Celsius, Raw: "200°C"}})
+	testTemps("Bake at 350 °F until golden", Step{
+		Text("Bake at "),
+		Temperature{Value: 350, Scale: Fahrenheit, Raw: "350 °F"},
+		Text(" until golden")})
+	testTemps("Warm to 200°c.", Step{
+		Text("Warm to "),
+		Temperature{Value: 200, Scale: Celsius, Raw: "200°c"},
+		Text(".")})
+	testTemps("Heat to 200°f", Step{
+		Text("Heat to "),
+		Temperature{Value: 200, Scale: Fahrenheit, Raw: "200°f"}})
+	testTemps("Heat to 90 degrees Celsius", Step{
+		Text("Heat to "),
+		Temperature{Value: 90, Scale: Celsius, Raw: "90 degrees Celsius"}})
+	testTemps("Roast at gas mark 6 in the #oven{}", Step{
+		Text("Roast at "),
+		Temperature{Value: 6, Scale: GasMark, Raw: "gas mark 6"},
+		Text(" in the "),
+		Cookware{Name: "oven", Qty: "", QtyVal: NoQty, Unit: ""}})
+
+	// Should be left alone
+	testTemps("Add 2 C of stock", Step{Text("Add 2 C of stock")})
+	testTemps("Add 2C water", Step{Text("Add 2C water")})
+	testTemps("Warm to 180C.", Step{Text("Warm to 180C.")})
+	testTemps("Use 2CF fans", Step{Text("Use 2CF fans")})
+	testTemps("Use A200C", Step{Text("Use A200C")})
+}
+
+func TestTemperatureConvert(t *testing.T) {
+	testConvert := func(in Temperature, scale TempScale, want float64) {
+		got := in.Convert(scale)
+		if got.Value != want || got.Scale != scale {
+			t.Fatalf("Failed to convert: %+v to %v\ngot: %+v\nwant: %v.",
+				in, scale, got, want)
+		}
+	}
+
+	testConvert(Temperature{Value: 200, Scale: Celsius}, Fahrenheit, 392)
+	testConvert(Temperature{Value: 350, Scale: Fahrenheit}, Celsius, 177)
+	testConvert(Temperature{Value: 6, Scale: GasMark}, Celsius, 200)
+	testConvert(Temperature{Value: 180, Scale: Celsius}, GasMark, 4)
+	testConvert(Temperature{Value: 200, Scale: Celsius}, Celsius, 200)
+}
+
+func TestTemperatureJSON(t *testing.T) {
+	want := Step{
+		Text("Heat to "),
+		Temperature{Value: 200, Scale: Celsius, Raw: "200°C"}}
+
+	data, err := json.Marshal(&want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Step
+	if err = json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("JSON round trip failed:\ngot: %#v\nwant: %#v.", got, want)
+	}
+}
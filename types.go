@@ -2,10 +2,12 @@ package cook
 
 import (
 	"encoding/json"
+	"strings"
 )
 
 // Chunks are the building blocks of recipe steps.
-// They are a union of Text, Ingredient, Timer and Cookware.
+// They are a union of Text, Ingredient, Timer and Cookware (and Temperature, if
+// the recipe has been post-processed with `ParseTemperatures`).
 type Chunk interface {
 	isChunk()
 	ToString() string
@@ -33,6 +35,14 @@ func (x Ingredient) ToString() string {
 	return x.Name
 }
 
+// Tests if an ingredient is a reference to another recipe, these are named by
+// their path relative to the referencing recipe's directory.
+//
+// e.g. `@./sauces/hollandaise{150%g}`
+func (x Ingredient) IsRecipeRef() bool {
+	return strings.HasPrefix(x.Name, "./") || strings.HasPrefix(x.Name, "../")
+}
+
 // Converts cookware to a string, with its name followed by qty and units if they
 // exist.
 func (x Cookware) ToString() string {
@@ -109,6 +119,8 @@ func (s *Step) MarshalJSON() ([]byte, error) {
 			tag = "cookware"
 		case Timer:
 			tag = "timer"
+		case Temperature:
+			tag = "temperature"
 		default:
 			opinionatedLanguageDevs = fixYourDamnCompilerWarnings
 			panic("Tried to encode unhandled Chunk type")
@@ -146,6 +158,14 @@ func (s *Step) UnmarshalJSON(data []byte) error {
 		// Handle text separate, as it requires no extra parsing
 		if tag == "text" {
 			step[i] = Text(chunkWrap["data"].(string))
+		} else if tag == "temperature" {
+			// Temperatures are not components, decode them directly
+			data, _ := json.Marshal(chunkWrap["data"])
+			var temp Temperature
+			if err := json.Unmarshal(data, &temp); err != nil {
+				return err
+			}
+			step[i] = temp
 		} else {
 			// Re-encode chunks to json to unmarshal as a component
 			dataMap := chunkWrap["data"].(map[string]interface{})
@@ -175,16 +195,38 @@ func (s *Step) UnmarshalJSON(data []byte) error {
 // and offer a continuous construction of the parsed `.cook` file.
 //
 // Additionally, the Ingredients, Cookware and Timer members provide a manifest
-// for each of the respective item classes.
+// for each of the respective item classes. `StepManifests[i]` lists which of
+// those items are used by `Steps[i]`.
 //
 // Recipes can be easily parsed from a string using the function `ParseRecipe`.
 type Recipe struct {
-	Name        string            `json:"name"`
-	Metadata    map[string]string `json:"metadata"`
-	Ingredients []Ingredient      `json:"ingredients"`
-	Cookware    []Cookware        `json:"cookware"`
-	Timers      []Timer           `json:"timers"`
-	Steps       []Step            `json:"steps"`
+	Name          string         `json:"name"`
+	Metadata      Metadata       `json:"metadata"`
+	Ingredients   []Ingredient   `json:"ingredients"`
+	Cookware      []Cookware     `json:"cookware"`
+	Timers        []Timer        `json:"timers"`
+	Steps         []Step         `json:"steps"`
+	StepManifests []StepManifest `json:"stepManifests"`
+}
+
+// A StepManifest lists the items used within a single step, as indices into
+// the recipe-wide `Ingredients`, `Cookware` and `Timers` manifests.
+//
+// e.g. `recipe.Ingredients[recipe.StepManifests[0].Ingredients[0]]` is the
+// first ingredient used in the first step.
+type StepManifest struct {
+	Ingredients []int `json:"ingredients"`
+	Cookware    []int `json:"cookware"`
+	Timers      []int `json:"timers"`
+}
+
+// Builds an empty `StepManifest`, with non-nil members
+func newStepManifest() StepManifest {
+	return StepManifest{
+		Ingredients: []int{},
+		Cookware:    []int{},
+		Timers:      []int{},
+	}
 }
 
 // Represents a generic `Component`, used in cooklang to define